@@ -0,0 +1,171 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/tunnel"
+
+	"github.com/limrun-inc/lim/logging"
+)
+
+// Device is an Android instance that is reachable by the local adb through
+// a tunnel.
+type Device struct {
+	// ADBPath is the path to adb executable.
+	ADBPath string
+
+	// Serial is the address adb knows the device by.
+	Serial string
+
+	id     string
+	tunnel *tunnel.Tunnel
+}
+
+// Connect starts a tunnel to the given Android instance and connects the
+// local adb to it. Call Close() when the device is no longer needed.
+func Connect(i *limrun.AndroidInstance, adbPath string) (*Device, error) {
+	t, err := tunnel.New(i.Status.AdbWebSocketURL, i.Status.Token, tunnel.WithADBPath(adbPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tunnel: %w", err)
+	}
+	if err := t.Start(); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("failed to start tunnel: %w", err)
+	}
+	slog.Info("tunnel connected", "instance", i.Metadata.ID, "addr", t.Addr(), "url", logging.RedactURL(i.Status.AdbWebSocketURL))
+	return &Device{
+		ADBPath: adbPath,
+		Serial:  t.Addr(),
+		id:      i.Metadata.ID,
+		tunnel:  t,
+	}, nil
+}
+
+// Command returns an adb command that targets the device.
+func (d *Device) Command(ctx context.Context, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, d.ADBPath, append([]string{"-s", d.Serial}, args...)...)
+}
+
+// Install installs the given APK files as a single app. More than one file
+// is treated as a split APK group.
+func (d *Device) Install(ctx context.Context, paths ...string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("no files to install")
+	}
+	args := []string{"install", "-r", "-t"}
+	if len(paths) > 1 {
+		args[0] = "install-multiple"
+	}
+	out, err := d.Command(ctx, append(args, paths...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, string(out))
+	}
+	return nil
+}
+
+// Close disconnects adb and stops the tunnel.
+func (d *Device) Close() {
+	_ = exec.Command(d.ADBPath, "disconnect", d.Serial).Run()
+	d.tunnel.Close()
+	slog.Info("tunnel disconnected", "instance", d.id, "addr", d.Serial)
+}
+
+// Screenshot writes a PNG screenshot of the device to w.
+func (d *Device) Screenshot(ctx context.Context, w io.Writer) error {
+	c := d.Command(ctx, "exec-out", "screencap", "-p")
+	var stderr bytes.Buffer
+	c.Stdout = w
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("failed to take screenshot: %w: %s", err, stderr.String())
+	}
+	return nil
+}
+
+// MaxRecordDuration is the longest recording screenrecord supports.
+const MaxRecordDuration = 3 * time.Minute
+
+// Record records the screen of the device for the given duration and saves
+// the MP4 video to localPath. Closing stop ends the recording early.
+func (d *Device) Record(ctx context.Context, duration time.Duration, localPath string, stop <-chan struct{}) error {
+	if duration <= 0 || duration > MaxRecordDuration {
+		return fmt.Errorf("duration must be between 1s and %s", MaxRecordDuration)
+	}
+	remotePath := fmt.Sprintf("/sdcard/lim-record-%d.mp4", time.Now().UnixNano())
+	secs := int(duration.Round(time.Second).Seconds())
+	if secs == 0 {
+		secs = 1
+	}
+	// screenrecord runs in the background of the device shell so that its
+	// PID is printed first, which lets only this recording be stopped and
+	// not the ones of other sessions on the same device.
+	c := d.Command(ctx, "shell", fmt.Sprintf("screenrecord --time-limit %d %s & echo $!; wait $!", secs, remotePath))
+	stdout, err := c.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to record screen: %w", err)
+	}
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to record screen: %w", err)
+	}
+	r := bufio.NewReader(stdout)
+	line, _ := r.ReadString('\n')
+	pid, err := strconv.Atoi(strings.TrimSpace(line))
+	if err != nil {
+		_ = c.Wait()
+		return fmt.Errorf("failed to start screenrecord: %q: %s", line, stderr.String())
+	}
+	done := make(chan error, 1)
+	go func() {
+		out, _ := io.ReadAll(r)
+		if err := c.Wait(); err != nil {
+			done <- fmt.Errorf("failed to record screen: %w: %s%s", err, string(out), stderr.String())
+			return
+		}
+		done <- nil
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return err
+		}
+	case <-stop:
+		// SIGINT lets screenrecord finalize the file before exiting.
+		_ = d.Command(ctx, "shell", "kill", "-INT", strconv.Itoa(pid)).Run()
+		<-done
+	}
+	defer func() {
+		_ = d.Command(context.Background(), "shell", "rm", "-f", remotePath).Run()
+	}()
+	if out, err := d.Command(ctx, "pull", remotePath, localPath).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to pull recording: %w: %s", err, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adb
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LogEntry is a single line of logcat output.
+type LogEntry struct {
+	Time     string `json:"time"`
+	PID      int    `json:"pid"`
+	TID      int    `json:"tid"`
+	Priority string `json:"priority"`
+	Tag      string `json:"tag"`
+	Message  string `json:"message"`
+}
+
+// threadtimeLine matches the default "threadtime" format of logcat, e.g.
+// 01-02 15:04:05.000  1234  5678 I ActivityManager: Start proc
+var threadtimeLine = regexp.MustCompile(`^(\d\d-\d\d \d\d:\d\d:\d\d\.\d+)\s+(\d+)\s+(\d+)\s+([VDIWEFS])\s+(.*?)\s*: ?(.*)$`)
+
+// ParseLogLine parses a logcat line in threadtime format. Lines that do not
+// match, e.g. "--------- beginning of main", are returned as message only.
+func ParseLogLine(line string) LogEntry {
+	m := threadtimeLine.FindStringSubmatch(line)
+	if m == nil {
+		return LogEntry{Message: line}
+	}
+	pid, _ := strconv.Atoi(m[2])
+	tid, _ := strconv.Atoi(m[3])
+	return LogEntry{
+		Time:     m[1],
+		PID:      pid,
+		TID:      tid,
+		Priority: m[4],
+		Tag:      m[5],
+		Message:  m[6],
+	}
+}
+
+// LogcatOptions configures the logcat command.
+type LogcatOptions struct {
+	// Follow keeps streaming new lines instead of exiting after the dump.
+	Follow bool
+
+	// Since limits the output to lines logged after the given time.
+	Since time.Time
+
+	// Package limits the output to the process of the given package.
+	Package string
+}
+
+// Logcat returns a logcat command in threadtime format for the device.
+func (d *Device) Logcat(ctx context.Context, opts LogcatOptions) (*exec.Cmd, error) {
+	args := []string{"logcat", "-v", "threadtime"}
+	if !opts.Follow {
+		args = append(args, "-d")
+	}
+	if !opts.Since.IsZero() {
+		args = append(args, "-T", fmt.Sprintf("%d.000", opts.Since.Unix()))
+	}
+	if opts.Package != "" {
+		out, err := d.Command(ctx, "shell", "pidof", "-s", opts.Package).Output()
+		pid := strings.TrimSpace(string(out))
+		if err != nil || pid == "" {
+			return nil, fmt.Errorf("package %s is not running", opts.Package)
+		}
+		args = append(args, "--pid", pid)
+	}
+	return d.Command(ctx, args...), nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package alias expands the user-defined aliases of lim commands, e.g.
+// droid for run android --rm --install-asset $1, into arguments.
+package alias
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expand returns the arguments the expansion of an alias stands for when
+// the alias is run with the given arguments. $1, $2 and so on are replaced
+// with the arguments at that position, $@ with all of them and $$ with a
+// single $. Arguments after the last one referred to are appended unless $@
+// is used.
+func Expand(expansion string, args []string) ([]string, error) {
+	tokens, err := Split(expansion)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	used, all := 0, false
+	for _, t := range tokens {
+		if t == "$@" {
+			out = append(out, args...)
+			all = true
+			continue
+		}
+		var b strings.Builder
+		for i := 0; i < len(t); i++ {
+			if t[i] != '$' || i+1 == len(t) {
+				b.WriteByte(t[i])
+				continue
+			}
+			if t[i+1] == '$' {
+				b.WriteByte('$')
+				i++
+				continue
+			}
+			j := i + 1
+			for j < len(t) && t[j] >= '0' && t[j] <= '9' {
+				j++
+			}
+			if j == i+1 {
+				b.WriteByte('$')
+				continue
+			}
+			n, err := strconv.Atoi(t[i+1 : j])
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid parameter %s, parameters start at $1", t[i:j])
+			}
+			if n > len(args) {
+				return nil, fmt.Errorf("missing argument %d", n)
+			}
+			b.WriteString(args[n-1])
+			used = max(used, n)
+			i = j - 1
+		}
+		out = append(out, b.String())
+	}
+	if !all {
+		out = append(out, args[used:]...)
+	}
+	return out, nil
+}
+
+// Split splits the expansion into arguments at white space like a shell.
+// Single quotes keep everything in them as is, double quotes and backslashes
+// outside of quotes keep white space and escape the character after a
+// backslash.
+func Split(s string) ([]string, error) {
+	var args []string
+	var b strings.Builder
+	inArg := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", s)
+			}
+			b.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inArg = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '"' || s[i+1] == '\\') {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, fmt.Errorf("unterminated double quote in %q", s)
+			}
+			inArg = true
+		case c == '\\' && i+1 < len(s):
+			i++
+			b.WriteByte(s[i])
+			inArg = true
+		default:
+			b.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args, nil
+}
+
+// Join is the reverse of Split, it quotes the arguments that need it.
+func Join(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a != "" && !strings.ContainsAny(a, " \t\n'\"\\") {
+			quoted[i] = a
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
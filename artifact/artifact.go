@@ -0,0 +1,288 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package artifact detects the type of app build outputs and turns them into
+// the files that can be installed on an instance.
+package artifact
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Kind is the type of an app artifact.
+type Kind string
+
+const (
+	KindAPK     Kind = "apk"
+	KindAAB     Kind = "aab"
+	KindXAPK    Kind = "xapk"
+	KindIOSApp  Kind = "app"
+	KindIPA     Kind = "ipa"
+	KindZip     Kind = "zip"
+	KindUnknown Kind = ""
+)
+
+// Info is the identity of an app read from its AndroidManifest.xml or
+// Info.plist. On iOS, Package is the bundle identifier, Version is
+// CFBundleShortVersionString and Build is CFBundleVersion. On Android,
+// Version is versionName and Build is versionCode.
+type Info struct {
+	Package string
+	Version string
+	Build   string
+}
+
+// Metadata returns the info as asset version metadata. It returns nil if
+// nothing is known about the app.
+func (i Info) Metadata() map[string]string {
+	m := map[string]string{}
+	for k, v := range map[string]string{
+		"package": i.Package,
+		"version": i.Version,
+		"build":   i.Build,
+	} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// Artifact is an app that is ready to be uploaded or installed.
+type Artifact struct {
+	Kind Kind
+
+	// Files are installed together, e.g. the split APKs of a bundle. Their
+	// base names are unique and can be used as asset names.
+	Files []string
+
+	Info Info
+}
+
+// Options configures how artifacts are converted.
+type Options struct {
+	// Dir is where converted files are written. It's up to the caller to
+	// remove it.
+	Dir string
+
+	// BundletoolPath is either the bundletool executable or its jar file.
+	BundletoolPath string
+}
+
+// Detect returns the kind of the artifact at the given path.
+func Detect(p string) (Kind, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return KindUnknown, err
+	}
+	ext := strings.ToLower(filepath.Ext(strings.TrimRight(p, `/\`)))
+	if info.IsDir() {
+		if ext == ".app" {
+			return KindIOSApp, nil
+		}
+		return KindUnknown, nil
+	}
+	switch ext {
+	case ".apk":
+		return KindAPK, nil
+	case ".aab":
+		return KindAAB, nil
+	case ".xapk":
+		return KindXAPK, nil
+	case ".ipa":
+		return KindIPA, nil
+	case ".zip":
+		return KindZip, nil
+	}
+	return KindUnknown, nil
+}
+
+// Prepare detects the kind of the artifact and converts it into installable
+// files. App bundles are converted into split APKs with bundletool, XAPK
+// archives are extracted and .app directories are zipped. Files of unknown
+// kind are returned as is without info. The info is only informative, so
+// failing to read it is logged and leaves it empty.
+func Prepare(ctx context.Context, p string, opts Options) (*Artifact, error) {
+	kind, err := Detect(p)
+	if err != nil {
+		return nil, err
+	}
+	a := &Artifact{Kind: kind}
+	var infoErr error
+	switch kind {
+	case KindAPK:
+		a.Files = []string{p}
+		var m manifest
+		if m, infoErr = readAPKManifest(p); infoErr == nil {
+			a.Info = Info{Package: m.Package, Version: m.VersionName, Build: m.VersionCode}
+		}
+	case KindAAB:
+		if a.Files, err = buildAPKs(ctx, p, opts); err != nil {
+			return nil, err
+		}
+		a.Info, infoErr = splitsInfo(a.Files)
+	case KindXAPK:
+		if a.Files, a.Info, err = extractXAPK(p, opts.Dir); err != nil {
+			return nil, err
+		}
+	case KindIOSApp:
+		a.Info, infoErr = readInfoPlistFile(filepath.Join(p, "Info.plist"))
+		name := filepath.Base(filepath.Clean(p))
+		out := filepath.Join(opts.Dir, strings.TrimSuffix(name, filepath.Ext(name))+".zip")
+		if err := zipDir(p, out); err != nil {
+			return nil, fmt.Errorf("failed to zip %s: %w", p, err)
+		}
+		a.Files = []string{out}
+	case KindIPA, KindZip:
+		a.Files = []string{p}
+		var info *Info
+		if info, infoErr = readZippedInfoPlist(p); info != nil {
+			a.Kind = KindIPA
+			a.Info = *info
+		}
+	default:
+		a.Files = []string{p}
+	}
+	if infoErr != nil {
+		slog.Warn("failed to read the package and version of the app", "path", p, "err", infoErr)
+	}
+	return a, nil
+}
+
+// splitsInfo returns the info of the base APK among the split APKs.
+func splitsInfo(files []string) (Info, error) {
+	for _, f := range files {
+		m, err := readAPKManifest(f)
+		if err != nil {
+			return Info{}, err
+		}
+		if m.Split == "" {
+			return Info{Package: m.Package, Version: m.VersionName, Build: m.VersionCode}, nil
+		}
+	}
+	return Info{}, fmt.Errorf("no base APK among %d split APK(s)", len(files))
+}
+
+// xapkManifest is the manifest.json at the root of an XAPK archive.
+type xapkManifest struct {
+	PackageName string `json:"package_name"`
+	VersionName string `json:"version_name"`
+	VersionCode string `json:"version_code"`
+}
+
+// extractXAPK extracts the APKs at the root of the XAPK archive into dir.
+// Expansion files are not extracted since they can't be installed with the
+// APKs. Failing to read the info is logged and leaves it empty.
+func extractXAPK(p, dir string) ([]string, Info, error) {
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, Info{}, fmt.Errorf("failed to open %s: %w", p, err)
+	}
+	defer r.Close()
+	stem := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	var files []string
+	var xm *xapkManifest
+	for _, f := range r.File {
+		switch {
+		case f.Name == "manifest.json":
+			rc, err := f.Open()
+			if err != nil {
+				return nil, Info{}, err
+			}
+			xm = &xapkManifest{}
+			err = json.NewDecoder(rc).Decode(xm)
+			_ = rc.Close()
+			if err != nil {
+				slog.Warn("failed to parse manifest.json of the XAPK", "path", p, "err", err)
+				xm = nil
+			}
+		case !strings.Contains(f.Name, "/") && strings.HasSuffix(strings.ToLower(f.Name), ".apk"):
+			out := filepath.Join(dir, stem+"-"+f.Name)
+			if err := extractFile(f, out); err != nil {
+				return nil, Info{}, fmt.Errorf("failed to extract %s from %s: %w", f.Name, p, err)
+			}
+			files = append(files, out)
+		}
+	}
+	if len(files) == 0 {
+		return nil, Info{}, fmt.Errorf("no APKs in %s", p)
+	}
+	if xm != nil && xm.PackageName != "" {
+		return files, Info{Package: xm.PackageName, Version: xm.VersionName, Build: xm.VersionCode}, nil
+	}
+	info, err := splitsInfo(files)
+	if err != nil {
+		slog.Warn("failed to read the package and version of the app", "path", p, "err", err)
+	}
+	return files, info, nil
+}
+
+func extractFile(f *zip.File, out string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	w, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, rc); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// readZippedInfoPlist reads the Info.plist of the app inside an .ipa or a
+// zipped .app. It returns nil if the archive doesn't contain an app.
+func readZippedInfoPlist(p string) (*Info, error) {
+	r, err := zip.OpenReader(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", p, err)
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		dir, file := path.Split(f.Name)
+		dir = strings.TrimPrefix(dir, "Payload/")
+		if file != "Info.plist" || strings.Count(dir, "/") != 1 || !strings.HasSuffix(dir, ".app/") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		info, err := readInfoPlist(rc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in %s: %w", f.Name, p, err)
+		}
+		return &info, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// writeZip creates a zip archive at p with the given files.
+func writeZip(t *testing.T, p string, files map[string][]byte) {
+	t.Helper()
+	f, err := os.Create(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, p string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestZipDirIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	app := filepath.Join(dir, "MyApp.app")
+	if err := os.MkdirAll(filepath.Join(app, "Frameworks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"Info.plist":            "plist",
+		"MyApp":                 "binary",
+		"Frameworks/Lib.dylib":  "library",
+		"Frameworks/zz-last.db": "data",
+	} {
+		if err := os.WriteFile(filepath.Join(app, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("Frameworks/Lib.dylib", filepath.Join(app, "Lib.dylib")); err != nil {
+		t.Fatal(err)
+	}
+	first := filepath.Join(dir, "first.zip")
+	if err := zipDir(app, first); err != nil {
+		t.Fatal(err)
+	}
+	// A rebuild touches the files without changing them.
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(app, "MyApp"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	second := filepath.Join(dir, "second.zip")
+	if err := zipDir(app+"/", second); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(readFile(t, first), readFile(t, second)) {
+		t.Error("zipping the same directory twice gave different bytes")
+	}
+	r, err := zip.OpenReader(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	want := []string{
+		"MyApp.app/",
+		"MyApp.app/Frameworks/",
+		"MyApp.app/Frameworks/Lib.dylib",
+		"MyApp.app/Frameworks/zz-last.db",
+		"MyApp.app/Info.plist",
+		"MyApp.app/Lib.dylib",
+		"MyApp.app/MyApp",
+	}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got entries %q, want %q", names, want)
+	}
+}
+
+func TestParseManifest(t *testing.T) {
+	m, err := parseManifest(readFile(t, "testdata/AndroidManifest.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := manifest{Package: "com.example.app", VersionName: "1.2.3", VersionCode: "42"}
+	if m != want {
+		t.Errorf("got %+v, want %+v", m, want)
+	}
+	if _, err := parseManifest([]byte("<manifest/>")); err == nil {
+		t.Error("parsed a text XML file")
+	}
+	if _, err := parseManifest(readFile(t, "testdata/AndroidManifest.xml")[:100]); err == nil {
+		t.Error("parsed a truncated file")
+	}
+}
+
+func TestReadInfoPlist(t *testing.T) {
+	want := Info{Package: "com.example.MyApp", Version: "2.1", Build: "210"}
+	for _, p := range []string{"testdata/Info.plist", "testdata/Info.binary.plist"} {
+		info, err := readInfoPlistFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info != want {
+			t.Errorf("got %+v from %s, want %+v", info, p, want)
+		}
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	manifestXML := readFile(t, "testdata/AndroidManifest.xml")
+	infoPlist := readFile(t, "testdata/Info.plist")
+	androidInfo := Info{Package: "com.example.app", Version: "1.2.3", Build: "42"}
+	iosInfo := Info{Package: "com.example.MyApp", Version: "2.1", Build: "210"}
+	dir := t.TempDir()
+	apk := filepath.Join(dir, "app.apk")
+	writeZip(t, apk, map[string][]byte{"AndroidManifest.xml": manifestXML})
+	ipa := filepath.Join(dir, "MyApp.ipa")
+	writeZip(t, ipa, map[string][]byte{"Payload/MyApp.app/Info.plist": infoPlist})
+	app := filepath.Join(dir, "MyApp.app")
+	if err := os.MkdirAll(app, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app, "Info.plist"), infoPlist, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		path  string
+		kind  Kind
+		files []string
+		info  Info
+	}{
+		{path: apk, kind: KindAPK, files: []string{apk}, info: androidInfo},
+		{path: ipa, kind: KindIPA, files: []string{ipa}, info: iosInfo},
+		{path: app, kind: KindIOSApp, files: []string{"MyApp.zip"}, info: iosInfo},
+	} {
+		out := t.TempDir()
+		a, err := Prepare(context.Background(), tc.path, Options{Dir: out})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range tc.files {
+			if !filepath.IsAbs(f) {
+				tc.files[i] = filepath.Join(out, f)
+			}
+		}
+		if a.Kind != tc.kind || !reflect.DeepEqual(a.Files, tc.files) || a.Info != tc.info {
+			t.Errorf("Prepare(%s) = %+v, want kind %q, files %q and info %+v", filepath.Base(tc.path), a, tc.kind, tc.files, tc.info)
+		}
+	}
+}
+
+func TestPrepareXAPK(t *testing.T) {
+	manifestXML := readFile(t, "testdata/AndroidManifest.xml")
+	base := filepath.Join(t.TempDir(), "base.apk")
+	writeZip(t, base, map[string][]byte{"AndroidManifest.xml": manifestXML})
+	baseAPK := readFile(t, base)
+	for _, tc := range []struct {
+		name  string
+		files map[string][]byte
+		want  []string
+		info  Info
+	}{
+		{
+			name: "with manifest.json",
+			files: map[string][]byte{
+				"manifest.json":        []byte(`{"package_name":"com.example.xapk","version_name":"3.0","version_code":"300"}`),
+				"com.example.xapk.apk": baseAPK,
+				"config.arm64_v8a.apk": []byte("split"),
+				"Android/obb/com.example.xapk/main.300.com.example.xapk.obb": []byte("expansion"),
+				"icon.png": []byte("icon"),
+			},
+			want: []string{"app-com.example.xapk.apk", "app-config.arm64_v8a.apk"},
+			info: Info{Package: "com.example.xapk", Version: "3.0", Build: "300"},
+		},
+		{
+			name:  "without manifest.json",
+			files: map[string][]byte{"base.apk": baseAPK},
+			want:  []string{"app-base.apk"},
+			info:  Info{Package: "com.example.app", Version: "1.2.3", Build: "42"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			xapk := filepath.Join(dir, "app.xapk")
+			writeZip(t, xapk, tc.files)
+			out := filepath.Join(dir, "out")
+			if err := os.Mkdir(out, 0755); err != nil {
+				t.Fatal(err)
+			}
+			a, err := Prepare(context.Background(), xapk, Options{Dir: out})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range a.Files {
+				if filepath.Dir(f) != out {
+					t.Errorf("%s is not extracted into %s", f, out)
+				}
+				got = append(got, filepath.Base(f))
+			}
+			slices.Sort(got)
+			if a.Kind != KindXAPK || !reflect.DeepEqual(got, tc.want) || a.Info != tc.info {
+				t.Errorf("got kind %q, files %q and info %+v, want files %q and info %+v", a.Kind, got, a.Info, tc.want, tc.info)
+			}
+			entries, err := os.ReadDir(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != len(tc.want) {
+				t.Errorf("extracted %d files, want only the %d APKs", len(entries), len(tc.want))
+			}
+		})
+	}
+	empty := filepath.Join(t.TempDir(), "empty.xapk")
+	writeZip(t, empty, map[string][]byte{"manifest.json": []byte(`{}`)})
+	if _, err := Prepare(context.Background(), empty, Options{Dir: t.TempDir()}); err == nil {
+		t.Error("prepared an XAPK without APKs")
+	}
+}
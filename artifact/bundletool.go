@@ -0,0 +1,105 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// deviceSpec describes the instances to bundletool so that it picks the
+// splits they need. ABIs are in the order of preference.
+const deviceSpec = `{
+  "supportedAbis": ["x86_64", "arm64-v8a"],
+  "supportedLocales": ["en-US"],
+  "screenDensity": 420,
+  "sdkVersion": 34
+}`
+
+// bundletoolCommand returns the command that runs bundletool with the given
+// arguments, going through java if the path is a jar file.
+func bundletoolCommand(ctx context.Context, bundletoolPath string, args ...string) *exec.Cmd {
+	if strings.HasSuffix(bundletoolPath, ".jar") {
+		return exec.CommandContext(ctx, "java", append([]string{"-jar", bundletoolPath}, args...)...)
+	}
+	return exec.CommandContext(ctx, bundletoolPath, args...)
+}
+
+// buildAPKs converts the app bundle into the split APKs for the instances.
+// bundletool signs them with the debug keystore in ~/.android if there is
+// one.
+func buildAPKs(ctx context.Context, p string, opts Options) ([]string, error) {
+	stem := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+	specPath := filepath.Join(opts.Dir, stem+"-device-spec.json")
+	if err := os.WriteFile(specPath, []byte(deviceSpec), 0o644); err != nil {
+		return nil, err
+	}
+	apksPath := filepath.Join(opts.Dir, stem+".apks")
+	if err := runBundletool(ctx, opts.BundletoolPath,
+		"build-apks",
+		"--bundle="+p,
+		"--output="+apksPath,
+		"--device-spec="+specPath,
+		"--overwrite",
+	); err != nil {
+		return nil, fmt.Errorf("failed to build APKs from %s: %w", p, err)
+	}
+	outDir := filepath.Join(opts.Dir, stem+"-splits")
+	if err := runBundletool(ctx, opts.BundletoolPath,
+		"extract-apks",
+		"--apks="+apksPath,
+		"--output-dir="+outDir,
+		"--device-spec="+specPath,
+	); err != nil {
+		return nil, fmt.Errorf("failed to extract APKs from %s: %w", p, err)
+	}
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return nil, err
+	}
+	// Splits are named after their module and configuration, e.g.
+	// base-master.apk, so the name of the bundle is prepended to tell them
+	// apart from the splits of other bundles in the asset storage.
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".apk") {
+			continue
+		}
+		out := filepath.Join(opts.Dir, stem+"-"+e.Name())
+		if err := os.Rename(filepath.Join(outDir, e.Name()), out); err != nil {
+			return nil, err
+		}
+		files = append(files, out)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("bundletool produced no APKs from %s", p)
+	}
+	return files, nil
+}
+
+func runBundletool(ctx context.Context, bundletoolPath string, args ...string) error {
+	c := bundletoolCommand(ctx, bundletoolPath, args...)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
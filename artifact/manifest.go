@@ -0,0 +1,259 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf16"
+)
+
+// Chunk types of the binary XML format that AndroidManifest.xml is compiled
+// into inside an APK.
+const (
+	chunkStringPool   = 0x0001
+	chunkXML          = 0x0003
+	chunkResourceMap  = 0x0180
+	chunkStartElement = 0x0102
+)
+
+// Resource IDs of the manifest attributes that are looked up when the
+// attribute names are stripped from the string pool.
+const (
+	resVersionCode = 0x0101021b
+	resVersionName = 0x0101021c
+)
+
+// Types of typed attribute values.
+const (
+	typeString = 0x03
+	typeIntDec = 0x10
+	typeIntHex = 0x11
+)
+
+const utf8Flag = 1 << 8
+
+// manifest holds the attributes of the root manifest element.
+type manifest struct {
+	Package     string
+	VersionName string
+	VersionCode string
+	Split       string
+}
+
+// readAPKManifest reads the root element of AndroidManifest.xml in the APK.
+func readAPKManifest(path string) (manifest, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer r.Close()
+	f, err := r.Open("AndroidManifest.xml")
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to find AndroidManifest.xml in %s: %w", path, err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return manifest{}, err
+	}
+	m, err := parseManifest(data)
+	if err != nil {
+		return manifest{}, fmt.Errorf("failed to parse AndroidManifest.xml in %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// parseManifest parses the binary XML until the first element, which is
+// manifest, and returns its attributes.
+func parseManifest(data []byte) (manifest, error) {
+	le := binary.LittleEndian
+	if len(data) < 8 || le.Uint16(data) != chunkXML {
+		return manifest{}, errors.New("not a binary XML file")
+	}
+	var strs []string
+	var resIDs []uint32
+	offset := int(le.Uint16(data[2:]))
+	for offset+8 <= len(data) {
+		typ := le.Uint16(data[offset:])
+		headerSize := int(le.Uint16(data[offset+2:]))
+		size := int(le.Uint32(data[offset+4:]))
+		if size < 8 || offset+size > len(data) {
+			return manifest{}, errors.New("truncated chunk")
+		}
+		chunk := data[offset : offset+size]
+		switch typ {
+		case chunkStringPool:
+			var err error
+			if strs, err = parseStringPool(chunk, headerSize); err != nil {
+				return manifest{}, err
+			}
+		case chunkResourceMap:
+			for i := headerSize; i+4 <= len(chunk); i += 4 {
+				resIDs = append(resIDs, le.Uint32(chunk[i:]))
+			}
+		case chunkStartElement:
+			return parseManifestElement(chunk, headerSize, strs, resIDs)
+		}
+		offset += size
+	}
+	return manifest{}, errors.New("no manifest element")
+}
+
+func parseManifestElement(chunk []byte, headerSize int, strs []string, resIDs []uint32) (manifest, error) {
+	le := binary.LittleEndian
+	str := func(i uint32) string {
+		if int(i) < len(strs) {
+			return strs[i]
+		}
+		return ""
+	}
+	ext := chunk[headerSize:]
+	if len(ext) < 20 {
+		return manifest{}, errors.New("truncated element")
+	}
+	if name := str(le.Uint32(ext[4:])); name != "manifest" {
+		return manifest{}, fmt.Errorf("unexpected root element %q", name)
+	}
+	attrStart := int(le.Uint16(ext[8:]))
+	attrSize := int(le.Uint16(ext[10:]))
+	attrCount := int(le.Uint16(ext[12:]))
+	var m manifest
+	for i := 0; i < attrCount; i++ {
+		a := attrStart + i*attrSize
+		if a+20 > len(ext) {
+			return manifest{}, errors.New("truncated attribute")
+		}
+		nameIdx := le.Uint32(ext[a+4:])
+		raw := le.Uint32(ext[a+8:])
+		dataType := ext[a+15]
+		value := le.Uint32(ext[a+16:])
+		var v string
+		switch {
+		case raw != 0xffffffff:
+			v = str(raw)
+		case dataType == typeString:
+			v = str(value)
+		case dataType == typeIntDec, dataType == typeIntHex:
+			v = strconv.FormatUint(uint64(value), 10)
+		}
+		var resID uint32
+		if int(nameIdx) < len(resIDs) {
+			resID = resIDs[nameIdx]
+		}
+		switch name := str(nameIdx); {
+		case name == "package":
+			m.Package = v
+		case name == "split":
+			m.Split = v
+		case name == "versionCode" || resID == resVersionCode:
+			m.VersionCode = v
+		case name == "versionName" || resID == resVersionName:
+			m.VersionName = v
+		}
+	}
+	return m, nil
+}
+
+// parseStringPool decodes all strings of a string pool chunk, which are
+// encoded either as UTF-8 or UTF-16.
+func parseStringPool(chunk []byte, headerSize int) ([]string, error) {
+	le := binary.LittleEndian
+	if headerSize < 28 || len(chunk) < headerSize {
+		return nil, errors.New("truncated string pool")
+	}
+	count := int(le.Uint32(chunk[8:]))
+	flags := le.Uint32(chunk[16:])
+	stringsStart := int(le.Uint32(chunk[20:]))
+	if headerSize+count*4 > len(chunk) {
+		return nil, errors.New("truncated string pool")
+	}
+	strs := make([]string, count)
+	for i := range strs {
+		o := stringsStart + int(le.Uint32(chunk[headerSize+i*4:]))
+		if o >= len(chunk) {
+			return nil, errors.New("string offset out of range")
+		}
+		var err error
+		if flags&utf8Flag != 0 {
+			strs[i], err = decodeUTF8(chunk[o:])
+		} else {
+			strs[i], err = decodeUTF16(chunk[o:])
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return strs, nil
+}
+
+func decodeUTF8(b []byte) (string, error) {
+	// The length in UTF-16 units comes first and the length in bytes second,
+	// each in one or two bytes.
+	skip := func(b []byte) (int, []byte, error) {
+		if len(b) < 1 {
+			return 0, nil, errors.New("truncated string")
+		}
+		if b[0]&0x80 == 0 {
+			return int(b[0]), b[1:], nil
+		}
+		if len(b) < 2 {
+			return 0, nil, errors.New("truncated string")
+		}
+		return int(b[0]&0x7f)<<8 | int(b[1]), b[2:], nil
+	}
+	_, b, err := skip(b)
+	if err != nil {
+		return "", err
+	}
+	n, b, err := skip(b)
+	if err != nil {
+		return "", err
+	}
+	if n > len(b) {
+		return "", errors.New("truncated string")
+	}
+	return string(b[:n]), nil
+}
+
+func decodeUTF16(b []byte) (string, error) {
+	le := binary.LittleEndian
+	if len(b) < 2 {
+		return "", errors.New("truncated string")
+	}
+	n := int(le.Uint16(b))
+	b = b[2:]
+	if n&0x8000 != 0 {
+		if len(b) < 2 {
+			return "", errors.New("truncated string")
+		}
+		n = (n&0x7fff)<<16 | int(le.Uint16(b))
+		b = b[2:]
+	}
+	if n*2 > len(b) {
+		return "", errors.New("truncated string")
+	}
+	units := make([]uint16, n)
+	for i := range units {
+		units[i] = le.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(units)), nil
+}
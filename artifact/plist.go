@@ -0,0 +1,61 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"howett.net/plist"
+)
+
+// infoPlist holds the keys of Info.plist that identify an app.
+type infoPlist struct {
+	BundleIdentifier string `plist:"CFBundleIdentifier"`
+	ShortVersion     string `plist:"CFBundleShortVersionString"`
+	BundleVersion    string `plist:"CFBundleVersion"`
+}
+
+func readInfoPlistFile(p string) (Info, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to open Info.plist: %w", err)
+	}
+	defer f.Close()
+	info, err := readInfoPlist(f)
+	if err != nil {
+		return Info{}, fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	return info, nil
+}
+
+// readInfoPlist decodes an Info.plist in any of the XML, binary or OpenStep
+// formats.
+func readInfoPlist(r io.Reader) (Info, error) {
+	// Decoding needs to seek, so the whole file is read into memory. It's
+	// small enough.
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Info{}, err
+	}
+	var p infoPlist
+	if _, err := plist.Unmarshal(b, &p); err != nil {
+		return Info{}, err
+	}
+	return Info{Package: p.BundleIdentifier, Version: p.ShortVersion, Build: p.BundleVersion}, nil
+}
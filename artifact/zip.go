@@ -0,0 +1,105 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package artifact
+
+import (
+	"archive/zip"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// zipEpoch is the modification time of all entries so that zipping the same
+// directory twice yields the same bytes and hence the same MD5, which lets
+// the asset storage skip uploading it again.
+var zipEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// zipDir zips the directory into out with the directory itself as the root
+// entry, e.g. MyApp.app/Info.plist. Entries are in lexical order, symbolic
+// links are kept as links and only the permission bits of the modes are
+// stored.
+func zipDir(dir, out string) error {
+	dir = filepath.Clean(dir)
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	root := filepath.Base(dir)
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr := &zip.FileHeader{
+			Name:     path.Join(root, filepath.ToSlash(rel)),
+			Method:   zip.Deflate,
+			Modified: zipEpoch,
+		}
+		hdr.SetMode(info.Mode().Type() | info.Mode().Perm())
+		switch {
+		case d.IsDir():
+			hdr.Name += "/"
+			hdr.Method = zip.Store
+			_, err := zw.CreateHeader(hdr)
+			return err
+		case d.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			w, err := zw.CreateHeader(hdr)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, target)
+			return err
+		case d.Type().IsRegular():
+			w, err := zw.CreateHeader(hdr)
+			if err != nil {
+				return err
+			}
+			src, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer src.Close()
+			_, err = io.Copy(w, src)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		_ = zw.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
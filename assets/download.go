@@ -0,0 +1,301 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"crypto/md5"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/packages/param"
+	"github.com/schollz/progressbar/v3"
+
+	"github.com/limrun-inc/lim/cache"
+)
+
+// maxDownloadAttempts is how many times a download is resumed before giving
+// up.
+const maxDownloadAttempts = 5
+
+// ErrUpToDate is returned by Download when the local file already has the
+// same content as the asset.
+var ErrUpToDate = errors.New("local file is up to date")
+
+// FileMD5 returns the hex encoded MD5 of the file at the given path. Files
+// that haven't changed since they were last hashed are not read again.
+func FileMD5(path string) (string, error) {
+	return cache.FileMD5(path)
+}
+
+// Download downloads the asset to the given path. The content is written to
+// a temporary file next to it first, which is resumed with range requests if
+// the transfer is interrupted and the asset still has the same MD5, and
+// renamed into place only after its MD5 is verified. If the signed download URL expires, a new one is requested.
+//
+// Progress is written to the given writer, or to a progress bar of its own
+// if it's nil.
+//
+// It returns ErrUpToDate without downloading anything if the file at path
+// already has the MD5 of the asset. If the local cache has the content, it's
+// copied from there instead of downloading it. Downloaded files are added to
+// the cache.
+func Download(ctx context.Context, lim limrun.Client, ass limrun.Asset, path string, progress io.Writer) error {
+	if ass.Md5 != "" {
+		if sum, err := FileMD5(path); err == nil && sum == ass.Md5 {
+			return ErrUpToDate
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if ok, err := cache.Restore(ass.Md5, path); err == nil && ok {
+		return nil
+	}
+	partPath := path + ".part"
+	// The MD5 of the asset is written next to the part file so that a part of
+	// some other content, e.g. of an asset that has been overwritten since, is
+	// not resumed.
+	sumPath := partPath + ".md5"
+	if b, err := os.ReadFile(sumPath); err != nil || ass.Md5 == "" || string(b) != ass.Md5 {
+		_ = os.Remove(partPath)
+	}
+	if err := os.WriteFile(sumPath, []byte(ass.Md5), 0644); err != nil {
+		return fmt.Errorf("failed to create file %s: %w", sumPath, err)
+	}
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", partPath, err)
+	}
+	defer file.Close()
+	// Hash what was downloaded by a previous run so that we don't need to
+	// read the whole file again at the end.
+	hasher := md5.New()
+	offset, err := io.Copy(hasher, file)
+	if err != nil {
+		return fmt.Errorf("failed to read partial file %s: %w", partPath, err)
+	}
+	var bar *progressbar.ProgressBar
+	start := func(offset, total int64) io.Writer {
+		if progress != nil {
+			return progress
+		}
+		if bar == nil {
+			bar = progressbar.DefaultBytes(total, "")
+		}
+		_ = bar.Set64(offset)
+		return bar
+	}
+	for attempt := 1; ; attempt++ {
+		var done bool
+		done, offset, err = downloadRange(ctx, ass.SignedDownloadURL, file, hasher, offset, start)
+		if done {
+			break
+		}
+		if errors.Is(err, errRangeNotSatisfiable) {
+			// Without an MD5 the part file is from this run, so it's complete.
+			if ass.Md5 == "" || fmt.Sprintf("%x", hasher.Sum(nil)) == ass.Md5 {
+				break
+			}
+			// The part file is longer than the asset, so it has some other
+			// content and we start over.
+			if err := file.Truncate(0); err != nil {
+				return fmt.Errorf("failed to truncate file %s: %w", partPath, err)
+			}
+			hasher.Reset()
+			offset = 0
+			continue
+		}
+		var expired *expiredURLError
+		if errors.As(err, &expired) {
+			refreshed, err := lim.Assets.Get(ctx, ass.ID, limrun.AssetGetParams{
+				IncludeDownloadURL: param.NewOpt(true),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to refresh download URL: %w", err)
+			}
+			ass.SignedDownloadURL = refreshed.SignedDownloadURL
+		}
+		if ctx.Err() != nil || attempt >= maxDownloadAttempts {
+			return fmt.Errorf("failed to download %s, run the command again to resume: %w", ass.Name, err)
+		}
+		t := time.NewTimer(time.Duration(attempt) * time.Second)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("failed to download %s, run the command again to resume: %w", ass.Name, ctx.Err())
+		case <-t.C:
+		}
+	}
+	if bar != nil {
+		_ = bar.Close()
+	}
+	if sum := fmt.Sprintf("%x", hasher.Sum(nil)); ass.Md5 != "" && sum != ass.Md5 {
+		_ = file.Close()
+		_ = os.Remove(partPath)
+		_ = os.Remove(sumPath)
+		return fmt.Errorf("downloaded file has MD5 %s but asset has %s", sum, ass.Md5)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		return fmt.Errorf("failed to move file into place: %w", err)
+	}
+	_ = os.Remove(sumPath)
+	if ass.Md5 != "" {
+		// The cache is only an optimization, so failing to fill it is not an
+		// error.
+		_ = cache.Store(path, ass.Md5)
+		cache.Remember(path, ass.Md5)
+	}
+	return nil
+}
+
+// errRangeNotSatisfiable means the server has no bytes after the offset,
+// which is the case if the part file is complete or longer than the asset.
+var errRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// expiredURLError means the signed URL is no longer accepted.
+type expiredURLError struct {
+	status string
+}
+
+func (e *expiredURLError) Error() string {
+	return fmt.Sprintf("signed URL rejected with %s", e.status)
+}
+
+// downloadRange downloads the file starting from the given offset and appends
+// it to file. It returns whether the download is complete along with the new
+// offset. The start function is called with the offset and the expected total
+// size once the response arrives and returns where to report progress.
+func downloadRange(ctx context.Context, url string, file *os.File, hasher hash.Hash, offset int64, start func(offset, total int64) io.Writer) (bool, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, offset, fmt.Errorf("failed to create download request: %w", err)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", "bytes="+strconv.FormatInt(offset, 10)+"-")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, offset, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the range so we start over.
+		if offset > 0 {
+			if err := file.Truncate(0); err != nil {
+				return false, offset, err
+			}
+			hasher.Reset()
+			offset = 0
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		return false, offset, errRangeNotSatisfiable
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return false, offset, &expiredURLError{status: resp.Status}
+	default:
+		b, _ := io.ReadAll(resp.Body)
+		return false, offset, fmt.Errorf("unexpected status %s: %s", resp.Status, string(b))
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return false, offset, err
+	}
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	n, err := io.Copy(io.MultiWriter(file, hasher, start(offset, total)), resp.Body)
+	offset += n
+	if err != nil {
+		return false, offset, err
+	}
+	return true, offset, nil
+}
+
+// Stream writes the content of the asset to w, e.g. stdout, and verifies its
+// MD5 once all of it is written. Since the written bytes cannot be taken
+// back, interrupted transfers are not resumed. The content is copied from the
+// local cache if it's there.
+func Stream(ctx context.Context, lim limrun.Client, ass limrun.Asset, w io.Writer) error {
+	if f, ok := cache.Open(ass.Md5); ok {
+		defer f.Close()
+		if _, err := io.Copy(w, f); err != nil {
+			return fmt.Errorf("failed to copy %s from the cache: %w", ass.Name, err)
+		}
+		return nil
+	}
+	resp, err := get(ctx, ass.SignedDownloadURL)
+	var expired *expiredURLError
+	if errors.As(err, &expired) {
+		refreshed, gerr := lim.Assets.Get(ctx, ass.ID, limrun.AssetGetParams{
+			IncludeDownloadURL: param.NewOpt(true),
+		})
+		if gerr != nil {
+			return fmt.Errorf("failed to refresh download URL: %w", gerr)
+		}
+		resp, err = get(ctx, refreshed.SignedDownloadURL)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", ass.Name, err)
+	}
+	defer resp.Body.Close()
+	bar := progressbar.DefaultBytes(resp.ContentLength, "")
+	hasher := md5.New()
+	if _, err := io.Copy(io.MultiWriter(w, hasher, bar), resp.Body); err != nil {
+		return fmt.Errorf("failed to download %s: %w", ass.Name, err)
+	}
+	_ = bar.Close()
+	if sum := fmt.Sprintf("%x", hasher.Sum(nil)); ass.Md5 != "" && sum != ass.Md5 {
+		return fmt.Errorf("downloaded content has MD5 %s but asset has %s", sum, ass.Md5)
+	}
+	return nil
+}
+
+// get sends a GET request and returns the response if it's successful.
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		resp.Body.Close()
+		return nil, &expiredURLError{status: resp.Status}
+	default:
+		b, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s: %s", resp.Status, string(b))
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/limrun-inc/lim/cache"
+	"github.com/limrun-inc/lim/errors"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/option"
+)
+
+// Delete deletes the asset with the given ID from the asset storage.
+//
+// The SDK has no method to delete assets, so DELETE /v1/assets/{id} is
+// called directly. The asset is looked up first so that a server that
+// doesn't serve that endpoint is told apart from a missing asset and
+// reported as unsupported. Deleted versions are removed from the index of
+// their name.
+func Delete(ctx context.Context, lim limrun.Client, id string) error {
+	if id == "" {
+		return fmt.Errorf("missing required id parameter")
+	}
+	ass, err := lim.Assets.Get(ctx, id, limrun.AssetGetParams{})
+	if err != nil {
+		return err
+	}
+	if err := lim.Delete(ctx, "v1/assets/"+id, nil, nil, option.WithHeader("Accept", "")); err != nil {
+		switch errors.StatusCode(err) {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return errors.Newf(errors.KindUnsupported, "the API server doesn't support deleting assets: %w", err)
+		}
+		return err
+	}
+	cache.ForgetAsset(id)
+	if err := forgetVersion(ctx, lim, ass.Name, id); err != nil {
+		return fmt.Errorf("deleted asset %s but failed to remove it from the versions of its name: %w", id, err)
+	}
+	return nil
+}
+
+// CreatedAt returns when the asset was created, or zero time if the API did
+// not return it.
+func CreatedAt(ass limrun.Asset) time.Time {
+	var fields struct {
+		CreatedAt time.Time `json:"createdAt"`
+	}
+	if ass.RawJSON() == "" {
+		return time.Time{}
+	}
+	_ = json.Unmarshal([]byte(ass.RawJSON()), &fields)
+	return fields.CreatedAt
+}
+
+// ParseAge parses a duration like time.ParseDuration but also accepts days
+// and weeks, e.g. 30d or 2w.
+func ParseAge(s string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.ParseFloat(n, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", s)
+			}
+			return time.Duration(v * float64(unit)), nil
+		}
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/packages/param"
+	"go.jetify.com/typeid/v2"
+	"golang.org/x/term"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+// AmbiguousError is returned when a name matches more than one asset.
+type AmbiguousError struct {
+	Ref        string
+	Candidates []limrun.Asset
+}
+
+func (e *AmbiguousError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d assets match %s, use an ID, the exact name or --latest:", len(e.Candidates), e.Ref)
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n  %s  %s", c.ID, c.Name)
+	}
+	return b.String()
+}
+
+// Kind returns errors.KindInvalidArgument since the reference needs to be
+// more specific.
+func (e *AmbiguousError) Kind() errors.Kind {
+	return errors.KindInvalidArgument
+}
+
+// NotFoundError is returned when no asset has the given name. Assets whose
+// name contains it are listed as suggestions.
+type NotFoundError struct {
+	Ref         string
+	Suggestions []limrun.Asset
+}
+
+func (e *NotFoundError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "asset with name %s not found", e.Ref)
+	if len(e.Suggestions) > 0 {
+		b.WriteString(", did you mean one of these?")
+		for _, c := range e.Suggestions {
+			fmt.Fprintf(&b, "\n  %s  %s", c.ID, c.Name)
+		}
+	}
+	return b.String()
+}
+
+// Kind returns errors.KindNotFound.
+func (e *NotFoundError) Kind() errors.Kind {
+	return errors.KindNotFound
+}
+
+// Resolver finds the asset a reference given by the user points to. A
+// reference is an asset ID, an exact asset name, name@version or name:tag.
+type Resolver struct {
+	// Latest makes the resolver pick the most recently created asset when
+	// more than one has the exact name instead of failing. Names that only
+	// contain the reference are never picked with it.
+	Latest bool
+
+	// Pick is called to choose among the candidates when more than one asset
+	// matches. If it's nil, an AmbiguousError is returned.
+	Pick func(ref string, candidates []limrun.Asset) (limrun.Asset, error)
+}
+
+// Resolve returns the asset the reference points to along with its download
+// URL.
+func (r Resolver) Resolve(ctx context.Context, lim limrun.Client, ref string) (limrun.Asset, error) {
+	if _, err := typeid.Parse(ref); err == nil {
+		fetched, err := lim.Assets.Get(ctx, ref, limrun.AssetGetParams{
+			IncludeDownloadURL: param.NewOpt(true),
+		})
+		if err != nil {
+			return limrun.Asset{}, fmt.Errorf("failed to get asset: %w", err)
+		}
+		return *fetched, nil
+	}
+	if ass, ok, err := ResolveVersion(ctx, lim, ref); err != nil {
+		return limrun.Asset{}, err
+	} else if ok {
+		return ass, nil
+	}
+	fetched, err := lim.Assets.List(ctx, limrun.AssetListParams{
+		NameFilter:         param.NewOpt(ref),
+		IncludeDownloadURL: param.NewOpt(true),
+	})
+	if err != nil {
+		return limrun.Asset{}, fmt.Errorf("failed to get asset with name %s: %w", ref, err)
+	}
+	var exact, similar []limrun.Asset
+	for _, ass := range *fetched {
+		switch {
+		case IsIndex(ass.Name):
+		case ass.Name == ref:
+			exact = append(exact, ass)
+		default:
+			similar = append(similar, ass)
+		}
+	}
+	switch {
+	case len(exact) == 1:
+		return exact[0], nil
+	case len(exact) > 1:
+		return r.choose(ref, exact)
+	}
+	// The name may only have versions.
+	idx, err := LoadIndex(ctx, lim, ref)
+	if err != nil {
+		return limrun.Asset{}, err
+	}
+	if v, ok := idx.Latest(); ok {
+		return r.Resolve(ctx, lim, v.AssetID)
+	}
+	// Names that only contain the reference are not used unless the user
+	// explicitly picks one of them.
+	if len(similar) == 0 || r.Latest || (r.Pick == nil && len(similar) == 1) {
+		return limrun.Asset{}, &NotFoundError{Ref: ref, Suggestions: similar}
+	}
+	return r.choose(ref, similar)
+}
+
+func (r Resolver) choose(ref string, candidates []limrun.Asset) (limrun.Asset, error) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return CreatedAt(candidates[i]).After(CreatedAt(candidates[j]))
+	})
+	if r.Latest {
+		return candidates[0], nil
+	}
+	if r.Pick != nil {
+		return r.Pick(ref, candidates)
+	}
+	return limrun.Asset{}, &AmbiguousError{Ref: ref, Candidates: candidates}
+}
+
+// InteractiveResolver returns a resolver that asks the user to pick among
+// the candidates when both stdin and stderr are terminals, and fails with the
+// list of candidates otherwise.
+func InteractiveResolver(latest bool) Resolver {
+	r := Resolver{Latest: latest}
+	if term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd())) {
+		r.Pick = func(ref string, candidates []limrun.Asset) (limrun.Asset, error) {
+			return prompt(os.Stdin, os.Stderr, ref, candidates)
+		}
+	}
+	return r
+}
+
+// prompt lists the candidates on out and reads the number of the chosen one
+// from in.
+func prompt(in io.Reader, out io.Writer, ref string, candidates []limrun.Asset) (limrun.Asset, error) {
+	_, _ = fmt.Fprintf(out, "%d assets match %s:\n", len(candidates), ref)
+	for i, c := range candidates {
+		created := ""
+		if t := CreatedAt(c); !t.IsZero() {
+			created = "  " + t.Local().Format(time.DateTime)
+		}
+		_, _ = fmt.Fprintf(out, "  [%d] %s  %s%s\n", i+1, c.ID, c.Name, created)
+	}
+	reader := bufio.NewReader(in)
+	for {
+		_, _ = fmt.Fprintf(out, "Pick one [1-%d]: ", len(candidates))
+		line, err := reader.ReadString('\n')
+		if n, convErr := strconv.Atoi(strings.TrimSpace(line)); convErr == nil && n >= 1 && n <= len(candidates) {
+			return candidates[n-1], nil
+		}
+		if err != nil {
+			return limrun.Asset{}, &AmbiguousError{Ref: ref, Candidates: candidates}
+		}
+	}
+}
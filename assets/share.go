@@ -0,0 +1,128 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/option"
+	"github.com/limrun-inc/go-sdk/packages/param"
+)
+
+// shortenerURL is the service that shortens share links. The signed URL is
+// sent to it, so shortening is opt-in.
+const shortenerURL = "https://is.gd/create.php?format=simple&url="
+
+// withExpiry asks the API to sign the URLs in the response with the given
+// lifetime instead of the default one.
+func withExpiry(expires time.Duration) option.RequestOption {
+	return option.WithQuery("urlExpiresInSeconds", strconv.Itoa(int(expires.Seconds())))
+}
+
+// DownloadURL returns a signed download URL of the asset that is valid for
+// the given duration if the server honors it, see URLExpiry.
+func DownloadURL(ctx context.Context, lim limrun.Client, id string, expires time.Duration) (string, error) {
+	ass, err := lim.Assets.Get(ctx, id, limrun.AssetGetParams{
+		IncludeDownloadURL: param.NewOpt(true),
+	}, withExpiry(expires))
+	if err != nil {
+		return "", fmt.Errorf("failed to get asset %s: %w", id, err)
+	}
+	if ass.SignedDownloadURL == "" {
+		return "", fmt.Errorf("asset %s has no content uploaded yet", id)
+	}
+	return ass.SignedDownloadURL, nil
+}
+
+// UploadURL creates the asset with the given name unless it exists and
+// returns a signed URL that its content can be uploaded to with a PUT
+// request, valid for the given duration if the server honors it.
+func UploadURL(ctx context.Context, lim limrun.Client, name string, expires time.Duration) (*limrun.AssetGetOrNewResponse, error) {
+	ass, err := lim.Assets.GetOrNew(ctx, limrun.AssetGetOrNewParams{
+		Name: name,
+	}, withExpiry(expires))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload URL of %s: %w", name, err)
+	}
+	return ass, nil
+}
+
+// URLExpiry returns when the signed URL expires and how long it was signed
+// for. Both V2 and V4 signatures of Google Cloud Storage and Amazon S3 are
+// understood, V2 URLs tell only the expiry so the lifetime is counted from
+// now.
+func URLExpiry(rawURL string) (time.Time, time.Duration, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return time.Time{}, 0, false
+	}
+	q := u.Query()
+	for _, prefix := range []string{"X-Goog-", "X-Amz-"} {
+		date, expires := q.Get(prefix+"Date"), q.Get(prefix+"Expires")
+		if date == "" || expires == "" {
+			continue
+		}
+		t, err := time.Parse("20060102T150405Z", date)
+		if err != nil {
+			return time.Time{}, 0, false
+		}
+		secs, err := strconv.Atoi(expires)
+		if err != nil {
+			return time.Time{}, 0, false
+		}
+		lifetime := time.Duration(secs) * time.Second
+		return t.Add(lifetime), lifetime, true
+	}
+	if expires := q.Get("Expires"); expires != "" {
+		secs, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return time.Time{}, 0, false
+		}
+		exp := time.Unix(secs, 0)
+		return exp, time.Until(exp), true
+	}
+	return time.Time{}, 0, false
+}
+
+// Shorten returns a short link that redirects to the given URL.
+func Shorten(ctx context.Context, long string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, shortenerURL+url.QueryEscape(long), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to shorten URL: %w", err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to shorten URL: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to shorten URL: %s", strings.TrimSpace(string(b)))
+	}
+	return strings.TrimSpace(string(b)), nil
+}
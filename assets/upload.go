@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/packages/param"
+	"github.com/schollz/progressbar/v3"
+
+	"github.com/limrun-inc/lim/artifact"
+	"github.com/limrun-inc/lim/cache"
+)
+
+// Upload uploads the local file to the asset storage with a progress bar on
+// stderr unless an asset with the same name and content exists already. Name
+// defaults to the base name of the file. Files that were uploaded with the
+// same name before and haven't changed since are skipped without hashing
+// them again once the API confirms that the asset still has their content,
+// in which case the returned asset has no signed URLs.
+func Upload(ctx context.Context, lim limrun.Client, path, name string) (*limrun.AssetGetOrNewResponse, error) {
+	f, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = filepath.Base(path)
+	}
+	if id, sum, ok := cache.AssetID(path, name); ok {
+		if ass, err := lim.Assets.Get(ctx, id, limrun.AssetGetParams{}); err == nil && ass.Name == name && ass.Md5 == sum {
+			fmt.Fprintf(os.Stderr, "%s is uploaded already\n", name)
+			return &limrun.AssetGetOrNewResponse{ID: id, Name: name, Md5: sum}, nil
+		}
+		cache.ForgetAsset(id)
+	}
+	fmt.Fprintf(os.Stderr, "%s\n", name)
+	bar := progressbar.DefaultBytes(
+		f.Size(),
+		"",
+	)
+	ass, err := lim.Assets.GetOrUpload(ctx, limrun.AssetGetOrUploadParams{
+		Name:           param.NewOpt(name),
+		Path:           path,
+		ProgressWriter: bar,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s: %w", path, err)
+	}
+	if err := bar.Close(); err != nil {
+		return nil, err
+	}
+	// The API returns the MD5 only if the content was there before, so it's
+	// hashed here for the cache.
+	if ass.Md5 == "" {
+		if sum, err := cache.FileMD5(path); err == nil {
+			ass.Md5 = sum
+		}
+	}
+	cache.RecordUpload(path, ass.Md5, ass.Name, ass.ID)
+	return ass, nil
+}
+
+// UploadVersion uploads the file as a new version of the named asset with the
+// given metadata attached. If one of the versions of the name has the same
+// content already, that version is returned instead.
+func UploadVersion(ctx context.Context, lim limrun.Client, path, name string, metadata map[string]string) (*limrun.AssetGetOrNewResponse, error) {
+	idx, err := LoadIndex(ctx, lim, name)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := FileMD5(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range idx.Versions {
+		if v.Md5 != sum {
+			continue
+		}
+		ass, err := lim.Assets.Get(ctx, v.AssetID, limrun.AssetGetParams{
+			IncludeDownloadURL: param.NewOpt(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get asset of %s: %w", VersionedName(name, v.Version), err)
+		}
+		fmt.Fprintf(os.Stderr, "%s is uploaded already\n", ass.Name)
+		return &limrun.AssetGetOrNewResponse{
+			ID:                ass.ID,
+			Name:              ass.Name,
+			SignedDownloadURL: ass.SignedDownloadURL,
+			Md5:               ass.Md5,
+		}, nil
+	}
+	version, err := FreeVersion(ctx, lim, idx)
+	if err != nil {
+		return nil, err
+	}
+	ass, err := Upload(ctx, lim, path, VersionedName(name, version))
+	if err != nil {
+		return nil, err
+	}
+	if err := RecordVersion(ctx, lim, name, Version{
+		Version:   version,
+		Asset:     ass.Name,
+		AssetID:   ass.ID,
+		Md5:       sum,
+		Metadata:  metadata,
+		CreatedAt: time.Now().UTC(),
+	}); err != nil {
+		return nil, err
+	}
+	return ass, nil
+}
+
+// UploadArtifact uploads the files of the artifact under their base names. If
+// versioned is true, they are uploaded as versions of their base names instead
+// with the package and version of the app attached as metadata if known.
+func UploadArtifact(ctx context.Context, lim limrun.Client, a *artifact.Artifact, versioned bool) ([]*limrun.AssetGetOrNewResponse, error) {
+	metadata := a.Info.Metadata()
+	var uploaded []*limrun.AssetGetOrNewResponse
+	for _, f := range a.Files {
+		var ass *limrun.AssetGetOrNewResponse
+		var err error
+		if versioned {
+			ass, err = UploadVersion(ctx, lim, f, filepath.Base(f), metadata)
+		} else {
+			ass, err = Upload(ctx, lim, f, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+		uploaded = append(uploaded, ass)
+	}
+	return uploaded, nil
+}
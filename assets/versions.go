@@ -0,0 +1,368 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/packages/param"
+)
+
+// The asset storage knows only names, so every version of an asset is stored
+// as a separate asset named <name>@<version> and the list of versions with
+// their tags, labels and metadata is kept in an index asset next to them.
+
+// indexPrefix is prepended to the name of the asset that the index belongs
+// to in order to get the name of the index asset.
+const indexPrefix = ".lim-versions/"
+
+// Version is a single version of a named asset.
+type Version struct {
+	// Version is unique among the versions of the same name.
+	Version string `json:"version"`
+
+	// Asset is the name of the asset that holds the content of this version.
+	Asset string `json:"asset"`
+
+	AssetID   string            `json:"assetId"`
+	Md5       string            `json:"md5,omitempty"`
+	Tags      []string          `json:"tags,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// Index is the list of versions of a named asset, oldest first.
+type Index struct {
+	Name     string    `json:"name"`
+	Versions []Version `json:"versions"`
+
+	// md5 is the MD5 of the index asset when it was loaded, empty if there
+	// was none.
+	md5 string
+}
+
+// maxIndexAttempts is how many times an index update is retried when the
+// index changes while it's updated.
+const maxIndexAttempts = 5
+
+// ErrIndexChanged is returned by SaveIndex when the index asset has been
+// changed by someone else since it was loaded.
+var ErrIndexChanged = errors.New("versions were changed by someone else in the meantime")
+
+// indexLocks holds a mutex for every name whose index is being updated.
+var indexLocks sync.Map
+
+// lockIndex serializes the updates of the index of the name within the
+// process and returns the function that releases it.
+func lockIndex(name string) func() {
+	m, _ := indexLocks.LoadOrStore(name, &sync.Mutex{})
+	mu := m.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// VersionedName returns the name of the asset that holds the given version.
+func VersionedName(name, version string) string {
+	return name + "@" + version
+}
+
+// IndexName returns the name of the index asset of the given name.
+func IndexName(name string) string {
+	return indexPrefix + name + ".json"
+}
+
+// IsIndex returns whether the asset name belongs to an index.
+func IsIndex(name string) bool {
+	return strings.HasPrefix(name, indexPrefix)
+}
+
+// IsVersion returns whether the asset name is that of a version, e.g.
+// app.apk@3.
+func IsVersion(name string) bool {
+	base, _, ok := SplitVersionRef(name)
+	return ok && name[len(base)] == '@'
+}
+
+// LoadIndex returns the index of the given name. The returned index is empty
+// if the name has no versions yet.
+func LoadIndex(ctx context.Context, lim limrun.Client, name string) (*Index, error) {
+	idx := &Index{Name: name}
+	indexName := IndexName(name)
+	fetched, err := lim.Assets.List(ctx, limrun.AssetListParams{
+		NameFilter:         param.NewOpt(indexName),
+		IncludeDownloadURL: param.NewOpt(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get versions of %s: %w", name, err)
+	}
+	for _, ass := range *fetched {
+		if ass.Name != indexName {
+			continue
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ass.SignedDownloadURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download versions of %s: %w", name, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			b, _ := io.ReadAll(resp.Body)
+			return nil, fmt.Errorf("failed to download versions of %s: %s", name, string(b))
+		}
+		if err := json.NewDecoder(resp.Body).Decode(idx); err != nil {
+			return nil, fmt.Errorf("failed to decode versions of %s: %w", name, err)
+		}
+		idx.md5 = ass.Md5
+		break
+	}
+	return idx, nil
+}
+
+// SaveIndex uploads the index, replacing the previous one. It returns
+// ErrIndexChanged without saving if the index asset doesn't have the content
+// it was loaded with anymore.
+//
+// The check and the upload are not atomic, so writers on different machines
+// can still overwrite each other's changes if they save at the same moment.
+func SaveIndex(ctx context.Context, lim limrun.Client, idx *Index) error {
+	current, err := indexMD5(ctx, lim, idx.Name)
+	if err != nil {
+		return err
+	}
+	if current != idx.md5 {
+		return ErrIndexChanged
+	}
+	f, err := os.CreateTemp("", "lim-index-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(idx); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if _, err := lim.Assets.GetOrUpload(ctx, limrun.AssetGetOrUploadParams{
+		Name: param.NewOpt(IndexName(idx.Name)),
+		Path: f.Name(),
+	}); err != nil {
+		return fmt.Errorf("failed to save versions of %s: %w", idx.Name, err)
+	}
+	return nil
+}
+
+// indexMD5 returns the MD5 of the index asset of the name, empty if there is
+// none.
+func indexMD5(ctx context.Context, lim limrun.Client, name string) (string, error) {
+	indexName := IndexName(name)
+	fetched, err := lim.Assets.List(ctx, limrun.AssetListParams{
+		NameFilter: param.NewOpt(indexName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get versions of %s: %w", name, err)
+	}
+	for _, ass := range *fetched {
+		if ass.Name == indexName {
+			return ass.Md5, nil
+		}
+	}
+	return "", nil
+}
+
+// updateIndex loads the index of the name, applies the change and saves it
+// if the change returns true. The update starts over with a freshly loaded
+// index if someone else changed it in the meantime.
+func updateIndex(ctx context.Context, lim limrun.Client, name string, change func(idx *Index) bool) error {
+	defer lockIndex(name)()
+	for attempt := 1; ; attempt++ {
+		idx, err := LoadIndex(ctx, lim, name)
+		if err != nil {
+			return err
+		}
+		if !change(idx) {
+			return nil
+		}
+		err = SaveIndex(ctx, lim, idx)
+		if !errors.Is(err, ErrIndexChanged) || attempt >= maxIndexAttempts {
+			return err
+		}
+	}
+}
+
+// NextVersion returns one more than the highest numeric version.
+func (idx *Index) NextVersion() string {
+	next := 1
+	for _, v := range idx.Versions {
+		if n, err := strconv.Atoi(v.Version); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	return strconv.Itoa(next)
+}
+
+// FreeVersion returns the next numeric version whose asset doesn't exist
+// yet. The index is only updated after the upload, so another push may have
+// taken the version that the index suggests already.
+func FreeVersion(ctx context.Context, lim limrun.Client, idx *Index) (string, error) {
+	n, _ := strconv.Atoi(idx.NextVersion())
+	for ; ; n++ {
+		name := VersionedName(idx.Name, strconv.Itoa(n))
+		fetched, err := lim.Assets.List(ctx, limrun.AssetListParams{
+			NameFilter: param.NewOpt(name),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to list assets: %w", err)
+		}
+		if !slices.ContainsFunc(*fetched, func(a limrun.Asset) bool { return a.Name == name }) {
+			return strconv.Itoa(n), nil
+		}
+	}
+}
+
+// RecordVersion adds the version to the index of the name. Versions added by
+// others in the meantime are kept.
+func RecordVersion(ctx context.Context, lim limrun.Client, name string, v Version) error {
+	return updateIndex(ctx, lim, name, func(idx *Index) bool {
+		idx.Add(v)
+		return true
+	})
+}
+
+// forgetVersion removes the version held by the given asset from the index of
+// its name. Assets whose name is not in name@version format are ignored.
+func forgetVersion(ctx context.Context, lim limrun.Client, assetName, assetID string) error {
+	i := strings.LastIndex(assetName, "@")
+	if i <= 0 {
+		return nil
+	}
+	return updateIndex(ctx, lim, assetName[:i], func(idx *Index) bool {
+		n := len(idx.Versions)
+		idx.Versions = slices.DeleteFunc(idx.Versions, func(v Version) bool {
+			return v.AssetID == assetID
+		})
+		return len(idx.Versions) != n
+	})
+}
+
+// Add adds the version, replacing the one with the same version string if it
+// exists. Tags are unique, so they are removed from all other versions.
+func (idx *Index) Add(v Version) {
+	for i := range idx.Versions {
+		idx.Versions[i].Tags = slices.DeleteFunc(idx.Versions[i].Tags, func(t string) bool {
+			return slices.Contains(v.Tags, t)
+		})
+	}
+	idx.Versions = slices.DeleteFunc(idx.Versions, func(e Version) bool {
+		return e.Version == v.Version
+	})
+	idx.Versions = append(idx.Versions, v)
+}
+
+// Find returns the version with the given version string or tag.
+func (idx *Index) Find(ref string) (Version, bool) {
+	for _, v := range idx.Versions {
+		if v.Version == ref {
+			return v, true
+		}
+	}
+	for _, v := range idx.Versions {
+		if slices.Contains(v.Tags, ref) {
+			return v, true
+		}
+	}
+	return Version{}, false
+}
+
+// Latest returns the most recently added version.
+func (idx *Index) Latest() (Version, bool) {
+	if len(idx.Versions) == 0 {
+		return Version{}, false
+	}
+	return idx.Versions[len(idx.Versions)-1], true
+}
+
+// ParseKeyValues parses a list of k=v pairs.
+func ParseKeyValues(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid key value pair %q, must be in k=v format", p)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// ResolveVersion resolves a name@version or name:tag reference through the
+// index of the name and returns the asset of that version with its download
+// URL. It returns false if the reference is not in either format or the name
+// has no such version.
+func ResolveVersion(ctx context.Context, lim limrun.Client, ref string) (limrun.Asset, bool, error) {
+	name, version, ok := SplitVersionRef(ref)
+	if !ok {
+		return limrun.Asset{}, false, nil
+	}
+	idx, err := LoadIndex(ctx, lim, name)
+	if err != nil {
+		return limrun.Asset{}, false, err
+	}
+	v, ok := idx.Find(version)
+	if !ok {
+		return limrun.Asset{}, false, nil
+	}
+	ass, err := lim.Assets.Get(ctx, v.AssetID, limrun.AssetGetParams{
+		IncludeDownloadURL: param.NewOpt(true),
+	})
+	if err != nil {
+		return limrun.Asset{}, false, fmt.Errorf("failed to get asset of %s: %w", ref, err)
+	}
+	return *ass, true, nil
+}
+
+// SplitVersionRef splits a name@version or name:tag reference into the name
+// and the version or tag.
+func SplitVersionRef(ref string) (string, string, bool) {
+	i := strings.LastIndexAny(ref, "@:")
+	if i <= 0 || i == len(ref)-1 {
+		return "", "", false
+	}
+	return ref[:i], ref[i+1:], true
+}
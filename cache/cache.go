@@ -0,0 +1,432 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cache keeps what is known about local files so that they don't
+// need to be hashed and looked up in the asset storage again, and a store
+// of downloaded assets addressed by their MD5.
+package cache
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/limrun-inc/lim/config"
+)
+
+// Entry is what is known about a local file as long as its size and
+// modification time stay the same.
+type Entry struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+	Md5     string    `json:"md5"`
+
+	// Assets maps the names the file is uploaded as to the asset IDs. Names
+	// are prefixed with the account key since the same name is a different
+	// asset for every API endpoint and key.
+	Assets map[string]string `json:"assets,omitempty"`
+}
+
+// Object is an asset content in the store.
+type Object struct {
+	Md5     string
+	Size    int64
+	ModTime time.Time
+}
+
+var (
+	mu       sync.Mutex
+	entries  map[string]Entry
+	loadedAt string
+)
+
+// Dir returns the directory of the cache, ~/.lim/cache unless configured
+// otherwise.
+func Dir() string {
+	if dir := viper.GetString(config.ConfigKeyCacheDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "lim-cache")
+	}
+	return filepath.Join(home, ".lim", "cache")
+}
+
+func indexPath() string {
+	return filepath.Join(Dir(), "index.json")
+}
+
+func objectsDir() string {
+	return filepath.Join(Dir(), "objects")
+}
+
+// load reads the index unless it's read already from the same place. mu
+// must be held.
+func load() {
+	if entries != nil && loadedAt == indexPath() {
+		return
+	}
+	entries = map[string]Entry{}
+	loadedAt = indexPath()
+	b, err := os.ReadFile(loadedAt)
+	if err != nil {
+		return
+	}
+	// A corrupt index is as good as an empty one.
+	_ = json.Unmarshal(b, &entries)
+}
+
+// save writes the index atomically. mu must be held.
+func save() error {
+	if err := os.MkdirAll(Dir(), 0700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(Dir(), "index-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), indexPath())
+}
+
+// lookup returns the key and the entry of the file if it hasn't changed since
+// the entry was recorded. mu must be held.
+func lookup(path string) (string, os.FileInfo, Entry, bool, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return "", nil, Entry{}, false, err
+	}
+	info, err := os.Stat(key)
+	if err != nil {
+		return "", nil, Entry{}, false, err
+	}
+	load()
+	e, ok := entries[key]
+	if !ok || e.Md5 == "" || e.Size != info.Size() || !e.ModTime.Equal(info.ModTime()) {
+		return key, info, Entry{}, false, nil
+	}
+	return key, info, e, true, nil
+}
+
+// cacheable returns whether the file is worth remembering. Temporary files
+// are not since they are gone by the next run.
+func cacheable(key string) bool {
+	if within(key, Dir()) {
+		return true
+	}
+	return !within(key, os.TempDir())
+}
+
+// within returns whether the path is inside the directory.
+func within(path, dir string) bool {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// FileMD5 returns the hex encoded MD5 of the file, hashing it only if it
+// changed since the last time.
+func FileMD5(path string) (string, error) {
+	mu.Lock()
+	key, info, e, ok, err := lookup(path)
+	mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return e.Md5, nil
+	}
+	sum, err := hashFile(key)
+	if err != nil {
+		return "", err
+	}
+	if !cacheable(key) {
+		return sum, nil
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	entries[key] = Entry{Size: info.Size(), ModTime: info.ModTime(), Md5: sum}
+	// The cache is only an optimization, so failing to write it is not an
+	// error.
+	_ = save()
+	return sum, nil
+}
+
+// Remember records the MD5 of the file, which is known to the caller, e.g.
+// because it was verified while downloading.
+func Remember(path, md5 string) {
+	mu.Lock()
+	defer mu.Unlock()
+	key, info, e, ok, err := lookup(path)
+	if err != nil || !cacheable(key) || (ok && e.Md5 == md5) {
+		return
+	}
+	entries[key] = Entry{Size: info.Size(), ModTime: info.ModTime(), Md5: md5}
+	_ = save()
+}
+
+// accountKey identifies the asset storage that uploads are recorded for
+// without keeping the API key in the cache.
+func accountKey() string {
+	sum := sha256.Sum256([]byte(viper.GetString(config.ConfigKeyAPIEndpoint) + "\n" + viper.GetString(config.ConfigKeyAPIKey)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// AssetID returns the ID of the asset that the file is uploaded as with the
+// given name to the configured asset storage along with the MD5 of the file,
+// if it hasn't changed since then. The asset may have been deleted or
+// overwritten by others since, so callers should confirm it with the API.
+func AssetID(path, name string) (string, string, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	_, _, e, ok, err := lookup(path)
+	if err != nil || !ok {
+		return "", "", false
+	}
+	id, ok := e.Assets[accountKey()+"/"+name]
+	return id, e.Md5, ok
+}
+
+// RecordUpload remembers that the file with the given MD5 is uploaded as the
+// asset with the given name and ID to the configured asset storage.
+func RecordUpload(path, md5, name, id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	key, info, e, ok, err := lookup(path)
+	if err != nil || md5 == "" || !cacheable(key) {
+		return
+	}
+	if !ok || e.Md5 != md5 {
+		e = Entry{Size: info.Size(), ModTime: info.ModTime(), Md5: md5}
+	}
+	if e.Assets == nil {
+		e.Assets = map[string]string{}
+	}
+	e.Assets[accountKey()+"/"+name] = id
+	entries[key] = e
+	_ = save()
+}
+
+// ForgetAsset forgets that any file is uploaded as the asset with the given
+// ID, e.g. because it's deleted.
+func ForgetAsset(id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	load()
+	var changed bool
+	for key, e := range entries {
+		for name, assetID := range e.Assets {
+			if assetID == id {
+				delete(e.Assets, name)
+				changed = true
+			}
+		}
+		entries[key] = e
+	}
+	if changed {
+		_ = save()
+	}
+}
+
+// Store adds a copy of the file with the given MD5 to the store.
+func Store(path, md5 string) error {
+	if md5 == "" {
+		return nil
+	}
+	if err := os.MkdirAll(objectsDir(), 0700); err != nil {
+		return err
+	}
+	obj := filepath.Join(objectsDir(), md5)
+	if err := replaceFile(path, obj); err != nil {
+		return err
+	}
+	Remember(obj, md5)
+	return nil
+}
+
+// Restore puts a copy of the content with the given MD5 at path if it's in
+// the store. It returns false if it's not.
+func Restore(md5, path string) (bool, error) {
+	obj, ok := objectPath(md5)
+	if !ok {
+		return false, nil
+	}
+	if err := replaceFile(obj, path); err != nil {
+		return false, err
+	}
+	Remember(path, md5)
+	return true, nil
+}
+
+// Open opens the content with the given MD5 if it's in the store.
+func Open(md5 string) (*os.File, bool) {
+	obj, ok := objectPath(md5)
+	if !ok {
+		return nil, false
+	}
+	f, err := os.Open(obj)
+	if err != nil {
+		return nil, false
+	}
+	return f, true
+}
+
+// objectPath returns the path of the content with the given MD5 in the store
+// after making sure that it hasn't been modified.
+func objectPath(md5 string) (string, bool) {
+	if md5 == "" {
+		return "", false
+	}
+	obj := filepath.Join(objectsDir(), md5)
+	sum, err := FileMD5(obj)
+	if err != nil {
+		return "", false
+	}
+	if sum != md5 {
+		_ = os.Remove(obj)
+		return "", false
+	}
+	return obj, true
+}
+
+// List returns the objects in the store, most recently stored first.
+func List() ([]Object, error) {
+	dirEntries, err := os.ReadDir(objectsDir())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var objs []Object
+	for _, d := range dirEntries {
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		objs = append(objs, Object{Md5: d.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].ModTime.After(objs[j].ModTime)
+	})
+	return objs, nil
+}
+
+// Size returns the total size of the cache on disk.
+func Size() (int64, error) {
+	var total int64
+	err := filepath.WalkDir(Dir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		total += info.Size()
+		return nil
+	})
+	return total, err
+}
+
+// Clear removes everything in the cache.
+func Clear() error {
+	mu.Lock()
+	defer mu.Unlock()
+	entries = nil
+	if err := os.RemoveAll(Dir()); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// replaceFile replaces dst with a copy of src. The copy is written next to
+// dst first so that dst is never left half written. Files are not hard
+// linked since changing one of them in place would change the other.
+func replaceFile(src, dst string) error {
+	tmp := filepath.Join(filepath.Dir(dst), "."+filepath.Base(dst)+".tmp")
+	if err := copyFile(src, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/limrun-inc/lim/config"
+)
+
+// setup points the cache and the temporary directory into a directory of the
+// test and returns a directory for files that can be cached.
+func setup(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	t.Cleanup(viper.Reset)
+	viper.Set(config.ConfigKeyCacheDir, filepath.Join(root, "cache"))
+	t.Setenv("TMPDIR", filepath.Join(root, "tmp"))
+	dir := filepath.Join(root, "files")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeFile(t *testing.T, p, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// rewrite changes the content of the file without changing its size and
+// modification time, which is what the cache goes by.
+func rewrite(t *testing.T, p, content string) {
+	t.Helper()
+	info, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, p, content)
+	if err := os.Chtimes(p, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileMD5(t *testing.T) {
+	dir := setup(t)
+	p := filepath.Join(dir, "app.apk")
+	writeFile(t, p, "first")
+	first, err := FileMD5(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rewrite(t, p, "other")
+	if sum, err := FileMD5(p); err != nil || sum != first {
+		t.Errorf("got %s, %v for an unchanged file, want the remembered %s", sum, err, first)
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(p, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if sum, err := FileMD5(p); err != nil || sum == first {
+		t.Errorf("got %s, %v for a changed file, want it hashed again", sum, err)
+	}
+
+	tmp := filepath.Join(os.TempDir(), "app.apk")
+	writeFile(t, tmp, "first")
+	if _, err := FileMD5(tmp); err != nil {
+		t.Fatal(err)
+	}
+	rewrite(t, tmp, "other")
+	if sum, err := FileMD5(tmp); err != nil || sum == first {
+		t.Errorf("got %s, %v for a temporary file, want it hashed again", sum, err)
+	}
+}
+
+func TestAssetID(t *testing.T) {
+	dir := setup(t)
+	p := filepath.Join(dir, "app.apk")
+	writeFile(t, p, "first")
+	sum, err := FileMD5(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	RecordUpload(p, sum, "app.apk", "asset_1")
+	if id, got, ok := AssetID(p, "app.apk"); !ok || id != "asset_1" || got != sum {
+		t.Errorf("got %s, %s, %v, want asset_1 with %s", id, got, ok, sum)
+	}
+	if _, _, ok := AssetID(p, "other.apk"); ok {
+		t.Error("found the asset under another name")
+	}
+	viper.Set(config.ConfigKeyAPIKey, "other account")
+	if _, _, ok := AssetID(p, "app.apk"); ok {
+		t.Error("found the asset of another account")
+	}
+	viper.Set(config.ConfigKeyAPIKey, "")
+	ForgetAsset("asset_1")
+	if _, _, ok := AssetID(p, "app.apk"); ok {
+		t.Error("found a forgotten asset")
+	}
+	RecordUpload(p, sum, "app.apk", "asset_1")
+	writeFile(t, p, "changed")
+	if _, _, ok := AssetID(p, "app.apk"); ok {
+		t.Error("found the asset of a changed file")
+	}
+}
+
+func TestStoreRestore(t *testing.T) {
+	dir := setup(t)
+	p := filepath.Join(dir, "app.apk")
+	writeFile(t, p, "content")
+	sum, err := FileMD5(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Store(p, sum); err != nil {
+		t.Fatal(err)
+	}
+	// The store doesn't follow changes of the stored file.
+	writeFile(t, p, "changed")
+	restored := filepath.Join(dir, "restored", "app.apk")
+	if err := os.MkdirAll(filepath.Dir(restored), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := Restore(sum, restored); err != nil || !ok {
+		t.Fatalf("got %v, %v, want the content restored", ok, err)
+	}
+	if b, _ := os.ReadFile(restored); string(b) != "content" {
+		t.Errorf("got %q, want the stored content", b)
+	}
+	// Nor does it follow changes of the restored file.
+	writeFile(t, restored, "changed again")
+	f, ok := Open(sum)
+	if !ok {
+		t.Fatal("the content is gone from the store")
+	}
+	f.Close()
+	objs, err := List()
+	if err != nil || len(objs) != 1 || objs[0].Md5 != sum || objs[0].Size != int64(len("content")) {
+		t.Errorf("got %+v, %v, want only the stored content", objs, err)
+	}
+
+	// A modified object is dropped.
+	writeFile(t, filepath.Join(Dir(), "objects", sum), "corrupt")
+	if ok, err := Restore(sum, restored); err != nil || ok {
+		t.Errorf("got %v, %v for a modified object, want it not restored", ok, err)
+	}
+	if objs, err := List(); err != nil || len(objs) != 0 {
+		t.Errorf("got %+v, %v, want the modified object removed", objs, err)
+	}
+}
+
+func TestSizeClear(t *testing.T) {
+	dir := setup(t)
+	if size, err := Size(); err != nil || size != 0 {
+		t.Errorf("got %d, %v for a missing cache, want 0", size, err)
+	}
+	p := filepath.Join(dir, "app.apk")
+	writeFile(t, p, "content")
+	if err := Store(p, "9a0364b9e99bb480dd25e1f0284c8555"); err != nil {
+		t.Fatal(err)
+	}
+	size, err := Size()
+	if err != nil || size <= int64(len("content")) {
+		t.Errorf("got %d, %v, want the object and the index counted", size, err)
+	}
+	if err := Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(Dir()); !os.IsNotExist(err) {
+		t.Errorf("got %v, want the cache directory removed", err)
+	}
+	if _, _, ok := AssetID(p, "app.apk"); ok {
+		t.Error("found an asset after clearing the cache")
+	}
+}
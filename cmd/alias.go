@@ -0,0 +1,335 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/limrun-inc/lim/alias"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+)
+
+// aliasNamePattern is what alias names look like. Config keys are case
+// insensitive and dots nest them, so neither upper case nor dots work.
+var aliasNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// AliasCmd represents the alias command
+var AliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage the aliases of long command lines.",
+	Long: `Aliases are kept in the aliases map of ~/.lim/config.yaml and expanded before
+the command line is parsed, e.g.
+
+aliases:
+  droid: run android --rm --region eu --install-asset $1
+
+makes lim droid a.apk,b.apk the same as
+lim run android --rm --region eu --install-asset a.apk,b.apk.
+
+Aliases in the config file in use, e.g. /etc/lim/config.yaml, work too but
+only the ones in ~/.lim/config.yaml are changed by these commands.
+
+$1, $2 and so on are replaced with the arguments at that position, $@ with all
+of them and $$ with a single $. Arguments after the last one referred to are
+appended, so lim droid a.apk --viewer none works too. Built-in commands take
+precedence over aliases with the same name.`,
+	Run: func(cmd *cobra.Command, args []string) {},
+}
+
+// aliasSetCmd adds or replaces an alias
+var aliasSetCmd = &cobra.Command{
+	Use:   "set [name] [expansion]...",
+	Short: "Adds or replaces an alias.",
+	Long: `The expansion is either a single argument that is split like a shell does, or
+several arguments taken as they are. Quote it so that the shell doesn't
+replace the parameters.
+
+Examples:
+
+$ lim alias set droid 'run android --rm --region eu --install-asset $1'
+$ lim alias set assets get asset
+`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if !aliasNamePattern.MatchString(name) {
+			return errors.Newf(errors.KindInvalidArgument, "invalid alias name %q, must be lower case letters, digits, - and _", name)
+		}
+		if c, ok := builtinCommand(name); ok {
+			return errors.Newf(errors.KindConflict, "alias %s would be shadowed by the built-in %s", name, c.CommandPath())
+		}
+		expansion := args[1]
+		if len(args) > 2 {
+			expansion = alias.Join(args[1:])
+		}
+		if _, err := alias.Split(expansion); err != nil {
+			return errors.WithKind(errors.KindInvalidArgument, err)
+		}
+		if err := updateAliases(func(aliases map[string]string) error {
+			aliases[name] = expansion
+			return nil
+		}); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "lim %s now runs lim %s\n", name, expansion)
+		return nil
+	},
+}
+
+// aliasListCmd lists the aliases
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the aliases.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases := configuredAliases()
+		if len(aliases) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No aliases defined")
+			return nil
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		table := tablewriter.NewWriter(cmd.OutOrStdout())
+		table.Header([]string{"Alias", "Expansion"})
+		data := make([][]string, len(names))
+		for i, name := range names {
+			data[i] = []string{name, aliases[name]}
+		}
+		if err := table.Bulk(data); err != nil {
+			return err
+		}
+		return table.Render()
+	},
+}
+
+// aliasDeleteCmd deletes an alias
+var aliasDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Deletes an alias.",
+	Args:  cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		if err := initializeConfig(cmd); err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var names []cobra.Completion
+		for name, expansion := range configuredAliases() {
+			if strings.HasPrefix(name, toComplete) {
+				names = append(names, cobra.CompletionWithDesc(name, expansion))
+			}
+		}
+		slices.Sort(names)
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := configuredAliases()[args[0]]; !ok {
+			return errors.Newf(errors.KindNotFound, "alias %s is not defined", args[0])
+		}
+		if err := updateAliases(func(aliases map[string]string) error {
+			if _, ok := aliases[args[0]]; !ok {
+				return errors.Newf(errors.KindInvalidArgument, "alias %s is defined in %s, which lim doesn't modify", args[0], viper.ConfigFileUsed())
+			}
+			delete(aliases, args[0])
+			return nil
+		}); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Deleted alias %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	AliasCmd.AddCommand(aliasSetCmd)
+	AliasCmd.AddCommand(aliasListCmd)
+	AliasCmd.AddCommand(aliasDeleteCmd)
+	RootCmd.AddCommand(AliasCmd)
+}
+
+// userConfigFile returns ~/.lim/config.yaml, which is the only config file
+// that aliases are written to.
+func userConfigFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".lim", "config.yaml"), nil
+}
+
+// configuredAliases returns the expansions of the aliases in the config in
+// use and in ~/.lim/config.yaml, which takes precedence since the aliases
+// are written there even if another config file is in use.
+func configuredAliases() map[string]string {
+	aliases := decodeAliases(viper.GetStringMap(config.ConfigKeyAliases))
+	if path, err := userConfigFile(); err == nil && path != viper.ConfigFileUsed() {
+		user := viper.New()
+		user.SetConfigFile(path)
+		if err := user.ReadInConfig(); err == nil {
+			maps.Copy(aliases, decodeAliases(user.GetStringMap(config.ConfigKeyAliases)))
+		}
+	}
+	return aliases
+}
+
+// decodeAliases returns the expansions of the aliases in the config value.
+// Expansions may be given as a list of arguments too, which are joined.
+func decodeAliases(m map[string]any) map[string]string {
+	aliases := map[string]string{}
+	for name, v := range m {
+		switch v := v.(type) {
+		case []any:
+			args := make([]string, len(v))
+			for i, arg := range v {
+				args[i] = fmt.Sprint(arg)
+			}
+			aliases[name] = alias.Join(args)
+		default:
+			aliases[name] = fmt.Sprint(v)
+		}
+	}
+	return aliases
+}
+
+// updateAliases calls update with the aliases in ~/.lim/config.yaml and
+// writes them back. Only the aliases node of the file is replaced, so the
+// rest of it, including comments, is kept as it is.
+func updateAliases(update func(aliases map[string]string) error) error {
+	path, err := userConfigFile()
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil && !goerrors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("config %s is not a map", path)
+	}
+	// Keys and values alternate in the content of a mapping node.
+	i := -1
+	for k := 0; k+1 < len(root.Content); k += 2 {
+		if root.Content[k].Value == config.ConfigKeyAliases {
+			i = k
+		}
+	}
+	var current map[string]any
+	if i >= 0 {
+		if err := root.Content[i+1].Decode(&current); err != nil {
+			return fmt.Errorf("failed to parse the aliases in config %s: %w", path, err)
+		}
+	}
+	aliases := decodeAliases(current)
+	if err := update(aliases); err != nil {
+		return err
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, name := range slices.Sorted(maps.Keys(aliases)) {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: aliases[name]},
+		)
+	}
+	switch {
+	case i >= 0 && len(aliases) == 0:
+		root.Content = slices.Delete(root.Content, i, i+2)
+	case i >= 0:
+		node.HeadComment, node.LineComment, node.FootComment = root.Content[i+1].HeadComment, root.Content[i+1].LineComment, root.Content[i+1].FootComment
+		root.Content[i+1] = node
+	case len(aliases) > 0:
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: config.ConfigKeyAliases}, node)
+	}
+	out, err := yaml.Marshal(&doc)
+	if err != nil {
+		return err
+	}
+	// The file is replaced atomically so that a failed write doesn't lose
+	// the rest of the config.
+	f, err := os.CreateTemp(filepath.Dir(path), "config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(out); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// expandAliases replaces an alias at the start of the arguments with its
+// expansion until they start with a built-in command or something that
+// isn't an alias, e.g. a plugin.
+func expandAliases(args []string) ([]string, error) {
+	seen := map[string]bool{}
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		if _, ok := builtinCommand(args[0]); ok {
+			return args, nil
+		}
+		if len(seen) == 0 {
+			if err := initializeConfig(RootCmd); err != nil {
+				return nil, err
+			}
+		}
+		expansion, ok := configuredAliases()[args[0]]
+		if !ok {
+			return args, nil
+		}
+		if seen[args[0]] {
+			return nil, errors.Newf(errors.KindInvalidArgument, "alias %s expands into itself", args[0])
+		}
+		seen[args[0]] = true
+		expanded, err := alias.Expand(expansion, args[1:])
+		if err != nil {
+			return nil, errors.WithKind(errors.KindInvalidArgument, fmt.Errorf("failed to expand alias %s: %w", args[0], err))
+		}
+		args = expanded
+	}
+	return args, nil
+}
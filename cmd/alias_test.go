@@ -0,0 +1,97 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+func TestAliases(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	h.server.PutAsset("other.apk", []byte("other apk"))
+	for _, args := range [][]string{
+		{"alias", "set", "show", "get asset $1"},
+		{"alias", "set", "all", "get", "asset"},
+		{"alias", "set", "loop", "loop"},
+	} {
+		if err := h.run(args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := h.run("show", "other.apk"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("all"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("show"); errors.KindOf(err) != errors.KindInvalidArgument {
+		t.Errorf("got %v, want an invalid argument error for the missing parameter", err)
+	}
+	if err := h.run("loop"); errors.KindOf(err) != errors.KindInvalidArgument {
+		t.Errorf("got %v, want an invalid argument error for the loop", err)
+	}
+	if err := h.run("alias", "set", "get", "push"); errors.KindOf(err) != errors.KindConflict {
+		t.Errorf("got %v, want a conflict with the built-in command", err)
+	}
+	if err := h.run("alias", "list"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("alias", "delete", "loop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("alias", "delete", "loop"); errors.KindOf(err) != errors.KindNotFound {
+		t.Errorf("got %v, want a not found error", err)
+	}
+	if err := h.run("alias", "list"); err != nil {
+		t.Fatal(err)
+	}
+	h.assertGolden("aliases")
+}
+
+func TestAliasesKeepConfig(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile(filepath.Join(h.dir, "home", ".lim", "config.yaml"), `# Settings of lim.
+update-check: false # no notices in CI
+
+aliases:
+    # Lists the assets.
+    all: get asset
+`)
+	if err := h.run("alias", "set", "show", "get asset $1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("alias", "delete", "all"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(h.dir, "home", ".lim", "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Settings of lim.
+update-check: false # no notices in CI
+aliases:
+    show: get asset $1
+`
+	if string(b) != want {
+		t.Errorf("got config\n%s\nwant\n%s", b, want)
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asset
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/packages/param"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneOlderThan  string
+	pruneKeepLast   int
+	pruneNameFilter string
+	pruneYes        bool
+	pruneDryRun     bool
+)
+
+func init() {
+	PruneCmd.PersistentFlags().StringVar(&pruneOlderThan, "older-than", "", "Only delete assets created longer ago than the given duration, e.g. 30d or 12h.")
+	PruneCmd.PersistentFlags().IntVar(&pruneKeepLast, "keep-last", 0, "Always keep the given number of most recently created matching assets.")
+	PruneCmd.PersistentFlags().StringVar(&pruneNameFilter, "name-filter", "", "Only consider assets whose name contains the given text.")
+	PruneCmd.PersistentFlags().BoolVarP(&pruneYes, "yes", "y", false, "Delete without asking for confirmation.")
+	PruneCmd.PersistentFlags().BoolVar(&pruneDryRun, "dry-run", false, "Only show the assets that would be deleted.")
+}
+
+// PruneCmd represents the prune command for assets
+var PruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Deletes old assets from the asset storage.",
+	Long: `Matching assets are listed first and deleted only after confirmation.
+Assets whose creation time is unknown are never deleted.
+
+Examples:
+
+$ lim assets prune --older-than 30d
+$ lim assets prune --name-filter nightly- --keep-last 5 --yes
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if pruneOlderThan == "" && pruneKeepLast == 0 {
+			return fmt.Errorf("at least one of --older-than or --keep-last is required")
+		}
+		var olderThan time.Duration
+		if pruneOlderThan != "" {
+			var err error
+			if olderThan, err = assets.ParseAge(pruneOlderThan); err != nil {
+				return err
+			}
+		}
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		params := limrun.AssetListParams{}
+		if pruneNameFilter != "" {
+			params.NameFilter = param.NewOpt(pruneNameFilter)
+		}
+		fetched, err := lim.Assets.List(cmd.Context(), params)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to list assets: %w", err)
+		}
+		var all []limrun.Asset
+		for _, ass := range *fetched {
+			if !assets.IsIndex(ass.Name) {
+				all = append(all, ass)
+			}
+		}
+		sort.SliceStable(all, func(i, j int) bool {
+			return assets.CreatedAt(all[i]).After(assets.CreatedAt(all[j]))
+		})
+		var toDelete []limrun.Asset
+		for i, ass := range all {
+			created := assets.CreatedAt(ass)
+			if i < pruneKeepLast || created.IsZero() {
+				continue
+			}
+			if olderThan > 0 && time.Since(created) < olderThan {
+				continue
+			}
+			toDelete = append(toDelete, ass)
+		}
+		if len(toDelete) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No assets to prune.")
+			return nil
+		}
+		table := tablewriter.NewWriter(cmd.OutOrStdout())
+		table.Header([]string{"ID", "Name", "Created"})
+		data := make([][]string, len(toDelete))
+		for i, ass := range toDelete {
+			data[i] = []string{ass.ID, ass.Name, assets.CreatedAt(ass).Local().Format(time.DateTime)}
+		}
+		if err := table.Bulk(data); err != nil {
+			return err
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		if pruneDryRun {
+			return nil
+		}
+		if !pruneYes {
+			fmt.Fprintf(cmd.OutOrStdout(), "Delete %d asset(s)? [y/N] ", len(toDelete))
+			answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+				return nil
+			}
+		}
+		for _, ass := range toDelete {
+			if err := assets.Delete(cmd.Context(), lim, ass.ID); err != nil {
+				return fmt.Errorf("failed to delete asset %s: %w", ass.ID, err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Deleted asset:", ass.ID)
+		}
+		return nil
+	},
+}
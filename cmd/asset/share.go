@@ -0,0 +1,115 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asset
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+
+	"github.com/mdp/qrterminal/v3"
+	"github.com/spf13/cobra"
+)
+
+var (
+	shareExpires time.Duration
+	shareShort   bool
+	shareQR      bool
+)
+
+func init() {
+	ShareCmd.PersistentFlags().DurationVar(&shareExpires, "expires", 24*time.Hour, "How long the link stays valid, e.g. 1h or 24h. The API server may not honor it, in which case the real lifetime is printed with a warning.")
+	ShareCmd.PersistentFlags().BoolVar(&shareShort, "short", false, "Print a short link instead. The signed URL is sent to the third-party is.gd service to shorten it, and anyone with either link can download the asset until it expires.")
+	ShareCmd.PersistentFlags().BoolVar(&shareQR, "qr", false, "Print the link as a QR code too, e.g. to open it on a phone.")
+}
+
+// ShareCmd represents the share command for assets
+var ShareCmd = &cobra.Command{
+	Use:   "share [ID or name]",
+	Short: "Prints a download link of an asset that anyone can use until it expires.",
+	Long: `The link is a signed URL that doesn't need an API key, so it can be handed to
+anyone who needs the file, e.g. a QA colleague. Treat it like a password
+until it expires.
+
+How long the link is valid is up to the API server, --expires is only a
+request. The time it actually expires at is printed on stderr.
+
+Examples:
+
+$ lim asset share app.apk
+$ lim asset share app.apk:nightly --expires 2h --qr
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.Assets),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if shareExpires <= 0 {
+			return fmt.Errorf("--expires must be positive")
+		}
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		ass, err := assets.InteractiveResolver(false).Resolve(cmd.Context(), lim, args[0])
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return err
+		}
+		u, err := assets.DownloadURL(cmd.Context(), lim, ass.ID, shareExpires)
+		if err != nil {
+			return err
+		}
+		warnExpiry(cmd, u, shareExpires)
+		if shareShort {
+			if u, err = assets.Shorten(cmd.Context(), u); err != nil {
+				return err
+			}
+		}
+		if shareQR {
+			qrterminal.GenerateHalfBlock(u, qrterminal.L, cmd.OutOrStdout())
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), u)
+		return nil
+	},
+}
+
+// warnExpiry tells on stderr when the link expires. The lifetime is asked
+// for with the urlExpiresInSeconds query parameter, which the SDK doesn't
+// document, so the server may ignore it and the link is valid for a
+// different time, which is warned about.
+func warnExpiry(cmd *cobra.Command, signedURL string, requested time.Duration) {
+	expiresAt, lifetime, ok := assets.URLExpiry(signedURL)
+	if !ok {
+		if cmd.Flags().Changed("expires") {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the link doesn't tell when it expires, so it may not be valid for %s\n", requested)
+		}
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Expires at %s\n", expiresAt.Local().Format(time.RFC1123))
+	if diff := lifetime - requested; diff < -time.Minute || diff > time.Minute {
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the API server ignored the requested lifetime, the link is valid for %s instead of %s\n", lifetime.Round(time.Minute), requested)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package asset
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	uploadURLExpires time.Duration
+)
+
+func init() {
+	UploadURLCmd.PersistentFlags().DurationVar(&uploadURLExpires, "expires", time.Hour, "How long the URL stays valid, e.g. 30m or 1h. The API server may not honor it, in which case the real lifetime is printed with a warning.")
+}
+
+// UploadURLCmd represents the upload-url command for assets
+var UploadURLCmd = &cobra.Command{
+	Use:   "upload-url [name]",
+	Short: "Prints a URL that the content of an asset can be uploaded to without an API key.",
+	Long: `The asset is created if it doesn't exist yet. Anyone with the URL can replace
+its content until the URL expires, so external CI systems can push builds
+without having an API key.
+
+Examples:
+
+$ lim asset upload-url app.apk --expires 30m
+$ curl -X PUT -H 'Content-Type: application/octet-stream' --upload-file app.apk "$URL"
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if uploadURLExpires <= 0 {
+			return fmt.Errorf("--expires must be positive")
+		}
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		ass, err := assets.UploadURL(cmd.Context(), lim, args[0], uploadURLExpires)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return err
+		}
+		warnExpiry(cmd, ass.SignedUploadURL, uploadURLExpires)
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Upload %s (%s) with:\n  curl -X PUT -H 'Content-Type: application/octet-stream' --upload-file <file> '<url>'\n", ass.Name, ass.ID)
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), ass.SignedUploadURL)
+		return nil
+	},
+}
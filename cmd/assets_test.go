@@ -0,0 +1,175 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+func TestAssetLifecycle(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile("app.apk", "fake apk")
+	h.writeFile("build/one.txt", "one")
+	h.writeFile("build/nested/two.txt", "two")
+	h.run("get", "asset")
+	h.run("push", "app.apk")
+	h.run("push", "build", "--prefix", "build-42/", "--concurrency", "1")
+	h.run("get", "asset")
+	h.run("get", "asset", "app.apk")
+	h.run("pull", "app.apk", "-o", "out")
+	h.run("pull", "app.apk", "-o", "out")
+	h.run("pull", "--name-filter", "build-42/", "-o", "out", "--concurrency", "1")
+	h.run("pull", "missing.apk")
+	h.run("delete", "asset", "app.apk")
+	h.run("get", "asset")
+	h.assertGolden("asset_lifecycle")
+
+	b, err := os.ReadFile("out/build-42/nested/two.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "two" {
+		t.Errorf("pulled content is %q, want %q", b, "two")
+	}
+}
+
+func TestPushStdinRequiresName(t *testing.T) {
+	h := newHarness(t)
+	if err := h.run("push", "-"); err == nil {
+		t.Error("push - without --name succeeded")
+	}
+	h.assertGolden("push_stdin_requires_name")
+}
+
+func TestAPIEndpointFlag(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("seeded.txt", []byte("seeded"))
+	h.runWithFlags("get", "asset")
+	h.assertGolden("api_endpoint_flag")
+}
+
+func TestPullSamePath(t *testing.T) {
+	h := newHarness(t)
+	first := h.server.PutAsset("app.apk", []byte("fake apk"))
+	second := h.server.PutAsset("./app.apk", []byte("other apk"))
+	if err := h.run("pull", first, second); errors.KindOf(err) != errors.KindConflict {
+		t.Errorf("got %v, want a conflict for assets pulled to the same file", err)
+	}
+	h.assertGolden("pull_same_path")
+}
+
+func TestDeleteUnsupported(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	next := h.handler
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			http.Error(w, `{"message":"method not allowed"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	if err := h.run("delete", "asset", "app.apk"); errors.KindOf(err) != errors.KindUnsupported {
+		t.Errorf("got %v, want an unsupported error", err)
+	}
+	h.assertGolden("delete_unsupported")
+}
+
+func TestPullLatestNeedsExactName(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app-debug.apk", []byte("debug"))
+	h.server.PutAsset("app-release.apk", []byte("release"))
+	if err := h.run("pull", "app", "--latest"); errors.KindOf(err) != errors.KindNotFound {
+		t.Errorf("got %v, want a not found error", err)
+	}
+	h.assertGolden("pull_latest_needs_exact_name")
+}
+
+func TestPullStdoutRefreshesExpiredURL(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	next := h.handler
+	var expired bool
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/storage/") && !expired {
+			expired = true
+			http.Error(w, "Request has expired", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	if err := h.run("pull", "app.apk", "-o", "-"); err != nil {
+		t.Fatal(err)
+	}
+	if !expired {
+		t.Error("the signed URL was not requested")
+	}
+	h.assertGolden("pull_stdout_refreshes_expired_url")
+}
+
+func TestShareExpires(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	// A server that ignores the requested lifetime.
+	next := h.handler
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		q.Del("urlExpiresInSeconds")
+		r.URL.RawQuery = q.Encode()
+		next.ServeHTTP(w, r)
+	})
+	if err := h.run("asset", "share", "app.apk", "--expires", "2h"); err != nil {
+		t.Fatal(err)
+	}
+	h.assertGolden("share_expires")
+}
+
+func TestPullDiscardsStalePart(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	h.server.PutAsset("other.apk", []byte("other apk"))
+	// A part of some other asset without the MD5 next to it.
+	h.writeFile("app.apk.part", "fake")
+	// A part with the MD5 of the asset that is longer than the asset.
+	h.writeFile("other.apk.part", "other apk and more")
+	h.writeFile("other.apk.part.md5", fmt.Sprintf("%x", md5.Sum([]byte("other apk"))))
+	if err := h.run("pull", "app.apk", "other.apk"); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"app.apk": "fake apk", "other.apk": "other apk"} {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("got %q in %s, want %q", b, name, want)
+		}
+		for _, p := range []string{name + ".part", name + ".part.md5"} {
+			if _, err := os.Stat(p); !os.IsNotExist(err) {
+				t.Errorf("%s is left behind", p)
+			}
+		}
+	}
+	h.assertGolden("pull_discards_stale_part")
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/cache"
+)
+
+// CacheCmd represents the cache command
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local cache of file hashes and downloaded assets.",
+	Long: `The cache remembers the MD5 of local files and the assets they are uploaded as
+so that unchanged files are neither hashed nor looked up again. Pulled assets
+are kept in it so that pulling the same content again doesn't download it.`,
+	Run: func(cmd *cobra.Command, args []string) {},
+}
+
+// cacheLsCmd lists the assets in the cache
+var cacheLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "Lists the downloaded assets in the cache.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		objs, err := cache.List()
+		if err != nil {
+			return err
+		}
+		if len(objs) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Cache is empty")
+			return nil
+		}
+		table := tablewriter.NewWriter(cmd.OutOrStdout())
+		table.Header([]string{"MD5", "Size", "Stored At"})
+		data := make([][]string, len(objs))
+		for i, o := range objs {
+			data[i] = []string{o.Md5, formatBytes(o.Size), o.ModTime.Local().Format(time.DateTime)}
+		}
+		if err := table.Bulk(data); err != nil {
+			return err
+		}
+		return table.Render()
+	},
+}
+
+// cacheSizeCmd prints the size of the cache
+var cacheSizeCmd = &cobra.Command{
+	Use:   "size",
+	Short: "Prints the size of the cache on disk.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		size, err := cache.Size()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\n", formatBytes(size), cache.Dir())
+		return nil
+	},
+}
+
+// cacheClearCmd clears the cache
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Removes everything in the cache.",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		size, err := cache.Size()
+		if err != nil {
+			return err
+		}
+		if err := cache.Clear(); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Cleared %s\n", formatBytes(size))
+		return nil
+	},
+}
+
+// formatBytes formats the size with a binary unit, e.g. 1.5 MiB.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func init() {
+	CacheCmd.AddCommand(cacheLsCmd)
+	CacheCmd.AddCommand(cacheSizeCmd)
+	CacheCmd.AddCommand(cacheClearCmd)
+	RootCmd.AddCommand(CacheCmd)
+}
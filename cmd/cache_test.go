@@ -0,0 +1,79 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/limrun-inc/lim/cache"
+)
+
+func TestCache(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	h.run("cache", "ls")
+	if err := h.run("pull", "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	// The content is pulled from the cache from now on.
+	next := h.handler
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/storage/") {
+			t.Errorf("downloaded %s although it's in the cache", r.URL.Path)
+		}
+		next.ServeHTTP(w, r)
+	})
+	if err := h.run("pull", "app.apk", "-o", "copy"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("pull", "app.apk", "-o", "-"); err != nil {
+		t.Fatal(err)
+	}
+	// Changing a pulled file doesn't change the cache.
+	h.writeFile("copy/app.apk", "changed")
+	if err := h.run("pull", "app.apk", "-o", "again"); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := os.ReadFile("again/app.apk"); err != nil || string(b) != "fake apk" {
+		t.Errorf("got %q, %v, want the content of the asset", b, err)
+	}
+
+	objs, err := cache.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range objs {
+		if err := os.Chtimes(filepath.Join(cache.Dir(), "objects", o.Md5), fixedNow, fixedNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.run("cache", "ls")
+	size, err := cache.Size()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.run("cache", "size")
+	h.run("cache", "clear")
+	h.mask(formatBytes(size), "$SIZE")
+	h.run("cache", "ls")
+	h.run("cache", "size")
+	h.assertGolden("cache")
+}
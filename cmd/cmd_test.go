@@ -0,0 +1,232 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/option"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+	"github.com/limrun-inc/lim/fake"
+)
+
+var update = flag.Bool("update", false, "Update the golden files in testdata instead of comparing against them.")
+
+// fixedNow is the time of the fake server so that the output is the same in
+// every run.
+var fixedNow = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+var (
+	durationPattern = regexp.MustCompile(`\b[0-9]+(\.[0-9]+)?(ns|µs|ms|s)\b`)
+	logTimePattern  = regexp.MustCompile(`\btime=\S+`)
+)
+
+// harness runs commands against a fake API server in an isolated home
+// directory and records a transcript of their output.
+type harness struct {
+	t          *testing.T
+	server     *fake.Server
+	handler    http.Handler
+	url        string
+	dir        string
+	testdata   string
+	transcript strings.Builder
+}
+
+func newHarness(t *testing.T) *harness {
+	t.Helper()
+	testdata, err := filepath.Abs("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := fake.New()
+	s.Now = func() time.Time { return fixedNow }
+	h := &harness{
+		t:       t,
+		server:  s,
+		handler: s.Handler(),
+	}
+	// The handler is looked up for every request so that tests can wrap
+	// it, e.g. to inject failures.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The config and the cache go to ~/.lim, which is in the temporary
+	// directory, and the environment must not leak into the commands.
+	t.Setenv("HOME", filepath.Join(dir, "home"))
+	t.Setenv("LIM_API_KEY", "")
+	t.Setenv("LIM_API_ENDPOINT", "")
+	// Files in the temporary directory are not cached, so the commands get
+	// one of their own outside of the harness directory.
+	tmp := filepath.Join(dir, "tmp")
+	work := filepath.Join(dir, "work")
+	for _, d := range []string{tmp, work} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("TMPDIR", tmp)
+	t.Chdir(work)
+	h.url = ts.URL
+	h.dir = dir
+	h.testdata = testdata
+	return h
+}
+
+// client returns a client of the fake server.
+func (h *harness) client() limrun.Client {
+	return limrun.NewClient(
+		option.WithBaseURL(h.url),
+		option.WithAPIKey("test"),
+		option.WithMaxRetries(0),
+	)
+}
+
+// writeFile creates a file in the working directory.
+func (h *harness) writeFile(name, content string) {
+	h.t.Helper()
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		h.t.Fatal(err)
+	}
+	if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// run executes the root command with the given arguments and the client of
+// the fake server injected, and adds the output to the transcript.
+func (h *harness) run(args ...string) error {
+	h.t.Helper()
+	return h.execute(config.WithClient(context.Background(), h.client()), args)
+}
+
+// runWithFlags is like run but lets the root command create the client from
+// the --api-endpoint and --api-key flags.
+func (h *harness) runWithFlags(args ...string) error {
+	h.t.Helper()
+	return h.execute(context.Background(), append([]string{"--api-endpoint", h.url, "--api-key", "test"}, args...))
+}
+
+func (h *harness) execute(ctx context.Context, args []string) error {
+	h.t.Helper()
+	reset(RootCmd)
+	viper.Reset()
+	var stdout, stderr bytes.Buffer
+	RootCmd.SetOut(&stdout)
+	RootCmd.SetErr(&stderr)
+	RootCmd.SetIn(strings.NewReader(""))
+	err := execute(ctx, args)
+	if err != nil {
+		printError(&stderr, err)
+	}
+	fmt.Fprintf(&h.transcript, "$ lim %s\n", strings.Join(args, " "))
+	h.transcript.WriteString(stdout.String())
+	if stderr.Len() > 0 {
+		fmt.Fprintf(&h.transcript, "[stderr]\n%s", stderr.String())
+	}
+	if err != nil {
+		fmt.Fprintf(&h.transcript, "[exit %d]\n", errors.ExitCode(err))
+	}
+	h.transcript.WriteString("\n")
+	return err
+}
+
+// reset puts the flags of the command and all its subcommands back to their
+// defaults and drops the context and settings of the previous execution,
+// since they live in package level variables that outlive an execution.
+func reset(c *cobra.Command) {
+	c.SetContext(nil)
+	c.SilenceUsage = false
+	for _, fs := range []*pflag.FlagSet{c.Flags(), c.PersistentFlags()} {
+		fs.VisitAll(func(f *pflag.Flag) {
+			if sv, ok := f.Value.(pflag.SliceValue); ok {
+				_ = sv.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	}
+	for _, sub := range c.Commands() {
+		reset(sub)
+	}
+}
+
+// mask replaces the value in the transcript with the placeholder. It's for
+// values that differ between runs and are checked by the test itself.
+func (h *harness) mask(value, placeholder string) {
+	s := strings.ReplaceAll(h.transcript.String(), value, placeholder)
+	h.transcript.Reset()
+	h.transcript.WriteString(s)
+}
+
+// normalize replaces the parts of the output that differ between runs.
+func (h *harness) normalize(s string) string {
+	if u, err := url.Parse(h.url); err == nil {
+		s = strings.ReplaceAll(s, u.Host, "$SERVER")
+	}
+	s = strings.ReplaceAll(s, h.dir, "$DIR")
+	s = strings.ReplaceAll(s, runtime.GOOS+"/"+runtime.GOARCH, "$OS/$ARCH")
+	s = strings.ReplaceAll(s, "lim-"+runtime.GOOS+"-"+runtime.GOARCH, "lim-$OS-$ARCH")
+	s = logTimePattern.ReplaceAllString(s, "time=$$TIME")
+	return durationPattern.ReplaceAllString(s, "$$DURATION")
+}
+
+// assertGolden compares the transcript against testdata/<name>.golden, or
+// writes it there with -update.
+func (h *harness) assertGolden(name string) {
+	h.t.Helper()
+	got := h.normalize(h.transcript.String())
+	path := filepath.Join(h.testdata, name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			h.t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		h.t.Fatalf("failed to read golden file, run the tests with -update to create it: %s", err)
+	}
+	if got != string(want) {
+		h.t.Errorf("output does not match %s, run the tests with -update if the change is expected\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
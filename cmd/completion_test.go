@@ -0,0 +1,37 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	h.server.PutAsset("other.apk", []byte("other apk"))
+	h.run("run", "android", "--connect=false", "--region", "eu-west")
+	h.run("__complete", "delete", "android", "")
+	h.run("__complete", "delete", "")
+	h.run("__complete", "delete", "android", "android_00000000000000000000000003", "")
+	h.run("__complete", "pull", "app")
+	h.run("__complete", "run", "android", "--install-asset", "app.apk,o")
+	h.run("__complete", "run", "android", "--region", "")
+	h.execute(context.Background(), []string{"__complete", "get", "asset", "--api-endpoint", h.url, "--api-key", "test", ""})
+	h.assertGolden("completion")
+}
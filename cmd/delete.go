@@ -19,14 +19,16 @@ package cmd
 import (
 	"fmt"
 	"github.com/limrun-inc/lim/cmd/deleteCmd"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/spf13/cobra"
 	"strings"
 )
 
 // DeleteCmd represents the delete command
 var DeleteCmd = &cobra.Command{
-	Use:  "delete",
-	Args: cobra.ExactArgs(1),
+	Use:               "delete",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.IDs),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
 		switch strings.Split(id, "_")[0] {
@@ -34,6 +36,8 @@ var DeleteCmd = &cobra.Command{
 			return deleteCmd.AndroidCmd.RunE(cmd, []string{id})
 		case "ios":
 			return deleteCmd.IOSCmd.RunE(cmd, []string{id})
+		case "asset":
+			return deleteCmd.AssetCmd.RunE(cmd, []string{id})
 		default:
 			return fmt.Errorf("invalid id: %s", id)
 		}
@@ -43,5 +47,6 @@ var DeleteCmd = &cobra.Command{
 func init() {
 	DeleteCmd.AddCommand(deleteCmd.AndroidCmd)
 	DeleteCmd.AddCommand(deleteCmd.IOSCmd)
+	DeleteCmd.AddCommand(deleteCmd.AssetCmd)
 	RootCmd.AddCommand(DeleteCmd)
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deleteCmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+
+	"github.com/limrun-inc/go-sdk"
+	"github.com/spf13/cobra"
+	"go.jetify.com/typeid/v2"
+)
+
+// AssetCmd represents the delete command for assets
+var AssetCmd = &cobra.Command{
+	Use:               "asset [ID or name]...",
+	Aliases:           []string{"ass", "assets"},
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: completion.Assets,
+	Short:             "Delete given assets from the asset storage.",
+	Long: `Examples:
+
+$ lim delete asset <ID>
+$ lim delete asset app.apk other.apk
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, ref := range args {
+			id, err := assetID(cmd.Context(), lim, ref)
+			if err == nil {
+				err = assets.Delete(cmd.Context(), lim, id)
+			}
+			if err != nil {
+				if errors.IsUnauthenticated(err) {
+					if err := config.Login(cmd.Context()); err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+					return nil
+				}
+				return fmt.Errorf("failed to delete asset %s: %w", ref, err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Deleted asset:", id)
+		}
+		return nil
+	},
+}
+
+// assetID returns the given reference if it's an ID, or the ID of the asset
+// it resolves to otherwise.
+func assetID(ctx context.Context, lim limrun.Client, ref string) (string, error) {
+	if _, err := typeid.Parse(ref); err == nil {
+		return ref, nil
+	}
+	ass, err := assets.InteractiveResolver(false).Resolve(ctx, lim, ref)
+	if err != nil {
+		return "", err
+	}
+	return ass.ID, nil
+}
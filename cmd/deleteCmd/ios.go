@@ -18,37 +18,41 @@ package deleteCmd
 
 import (
 	"fmt"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
 
-	"github.com/limrun-inc/go-sdk"
 	"github.com/spf13/cobra"
 )
 
 // IOSCmd represents the delete command for iOS
 var IOSCmd = &cobra.Command{
-	Use:     "ios [ID]",
-	Aliases: []string{"i", "ios"},
-	Args:    cobra.ExactArgs(1),
-	Short:   "Delete given iOS instance.",
+	Use:               "ios [ID]",
+	Aliases:           []string{"i", "ios"},
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.IOSInstances),
+	Short:             "Delete given iOS instance.",
 	Long: `Examples:
 
 $ lim delete ios <ID>
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		id := args[0]
-		lim := cmd.Context().Value("lim").(limrun.Client)
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
 		if err := lim.IosInstances.Delete(cmd.Context(), id); err != nil {
 			if errors.IsUnauthenticated(err) {
 				if err := config.Login(cmd.Context()); err != nil {
 					return err
 				}
-				fmt.Println("You are logged in now")
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 				return nil
 			}
 			return fmt.Errorf("failed to delete iOS instance: %w", err)
 		}
-		fmt.Println("Deleted iOS instance:", id)
+		fmt.Fprintln(cmd.OutOrStdout(), "Deleted iOS instance:", id)
 		return nil
 	},
 }
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/fake"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	port       int
+	host       string
+	readyAfter time.Duration
+	apiKey     string
+)
+
+func init() {
+	ServerCmd.PersistentFlags().IntVar(&port, "port", 8080, "Port to listen on.")
+	ServerCmd.PersistentFlags().StringVar(&host, "host", "127.0.0.1", "Address to listen on.")
+	ServerCmd.PersistentFlags().DurationVar(&readyAfter, "ready-after", 2*time.Second, "How long new instances stay in creating state.")
+	ServerCmd.PersistentFlags().StringVar(&apiKey, "require-api-key", "", "Only accept requests with the given API key. Any key is accepted by default.")
+}
+
+// ServerCmd represents the dev server command
+var ServerCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Starts a fake Limrun API server in memory.",
+	Long: `The server implements the asset and instance endpoints with signed upload and
+download URLs. Instances become ready after --ready-after and their ADB
+WebSocket echoes everything back. Nothing is persisted.
+
+Examples:
+
+$ lim dev server --port 8080
+$ lim --api-endpoint http://127.0.0.1:8080 --api-key fake run android --connect=false
+`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{config.AnnotationSession: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := fake.New()
+		s.ReadyAfter = readyAfter
+		s.APIKey = apiKey
+		if err := s.Start(net.JoinHostPort(host, strconv.Itoa(port))); err != nil {
+			return err
+		}
+		defer s.Close()
+		fmt.Fprintf(cmd.OutOrStdout(), "Fake Limrun API is listening on %s\n", s.URL())
+		fmt.Fprintf(cmd.OutOrStdout(), "Point lim at it with --api-endpoint %s or LIM_API_ENDPOINT=%s\n", s.URL(), s.URL())
+		fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop.")
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+		return nil
+	},
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	limrun "github.com/limrun-inc/go-sdk"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+func TestExitCodes(t *testing.T) {
+	h := newHarness(t)
+	lim := h.client()
+	i, err := lim.IosInstances.New(context.Background(), limrun.IosInstanceNewParams{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		args []string
+		kind errors.Kind
+	}{
+		{args: []string{"frobnicate"}, kind: errors.KindInvalidArgument},
+		{args: []string{"get", "asset", "--no-such-flag"}, kind: errors.KindInvalidArgument},
+		{args: []string{"delete", "android"}, kind: errors.KindInvalidArgument},
+		{args: []string{"delete", "android", "android_00000000000000000000000042"}, kind: errors.KindNotFound},
+		{args: []string{"pull", "missing.apk", "--error-format=json"}, kind: errors.KindNotFound},
+		{args: []string{"install", "ios", i.Metadata.ID, "MyApp.zip"}, kind: errors.KindUnsupported},
+		{args: []string{"screenshot", i.Metadata.ID}, kind: errors.KindUnsupported},
+		{args: []string{"get", "ios", "--error-format=json"}, kind: ""},
+	} {
+		err := h.run(tc.args...)
+		if got := errors.KindOf(err); got != tc.kind {
+			t.Errorf("lim %v failed with kind %q, want %q", tc.args, got, tc.kind)
+		}
+	}
+	h.assertGolden("exit_codes")
+}
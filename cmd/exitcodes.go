@@ -0,0 +1,53 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	RootCmd.AddCommand(ExitCodesCmd)
+}
+
+// ExitCodesCmd is the help topic of the exit codes.
+var ExitCodesCmd = &cobra.Command{
+	Use:   "exit-codes",
+	Short: "Exit codes and the kinds of errors they stand for.",
+	Long: `lim exits with a code that tells the kind of the failure. The codes are stable
+so that scripts can branch on them.
+
+  0  success
+  1  unknown            Any error that isn't classified.
+  2  invalid_argument   A missing or invalid argument, an unknown flag or command.
+  3  unauthenticated    The API key is missing or invalid.
+  4  not_found          The instance or asset doesn't exist.
+  5  conflict           The resource exists already or was changed concurrently.
+  6  quota_exceeded     A limit of the organization is reached or the API rate
+                        limits the requests.
+  7  timeout            A deadline passed before the operation completed.
+  8  network            The API couldn't be reached.
+  9  unsupported        The API server doesn't support the operation, e.g. an
+                        older server or one that doesn't expose it.
+
+With --error-format=json, the error is printed to stderr as a JSON object:
+
+{"error":{"kind":"not_found","message":"...","exitCode":4,"statusCode":404,"requestId":"..."}}
+
+statusCode and requestId are present only if the error is a response of the API.
+`,
+}
@@ -18,6 +18,7 @@ package get
 
 import (
 	"fmt"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
 
@@ -39,6 +40,7 @@ $ lim get android
 Get a specific Android instance:
 $ lim get android <ID>
 `,
+	ValidArgsFunction: completion.FirstArg(completion.AndroidInstances),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var id string
 		if len(args) > 1 {
@@ -46,7 +48,10 @@ $ lim get android <ID>
 		}
 		var data [][]string
 		table := tablewriter.NewWriter(cmd.OutOrStdout())
-		lim := cmd.Context().Value("lim").(limrun.Client)
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
 		table.Header([]string{"ID", "Name", "Region", "State"})
 		var instances []limrun.AndroidInstance
 		if id == "" {
@@ -58,7 +63,7 @@ $ lim get android <ID>
 					if err := config.Login(cmd.Context()); err != nil {
 						return err
 					}
-					fmt.Println("You are logged in now")
+					fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 					return nil
 				}
 				return fmt.Errorf("failed to list android instances: %w", err)
@@ -18,33 +18,41 @@ package get
 
 import (
 	"fmt"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	"github.com/limrun-inc/go-sdk"
 	"github.com/limrun-inc/go-sdk/packages/param"
+	"go.jetify.com/typeid/v2"
 )
 
 var (
 	assetName          string
 	includeDownloadUrl bool
 	includeUploadUrl   bool
+	listVersions       bool
 )
 
 func init() {
 	GetAssetCmd.PersistentFlags().StringVar(&assetName, "name", "", "The name of the asset")
 	GetAssetCmd.PersistentFlags().BoolVar(&includeDownloadUrl, "download-url", false, "Include a download URL in the response")
 	GetAssetCmd.PersistentFlags().BoolVar(&includeUploadUrl, "upload-url", false, "Include an upload URL in the response")
+	GetAssetCmd.PersistentFlags().BoolVar(&listVersions, "versions", false, "List the versions of the named asset with their tags, labels and metadata")
 }
 
 // GetAssetCmd represents the get command for Assets
 var GetAssetCmd = &cobra.Command{
-	Use:     "asset [ID]",
+	Use:     "asset [ID or name]",
 	Aliases: []string{"ass", "assets"},
-	Short:   "Get all assets, or specific asset if an ID is provided.",
+	Short:   "Get all assets, or specific asset if an ID or name is provided.",
 	Long: `Examples:
 
 Get all asset:
@@ -52,7 +60,13 @@ $ lim get assets
 
 Get a specific asset:
 $ lim get asset <ID>
+$ lim get asset app.apk@3
+$ lim get asset app.apk:stable
+
+List the versions of an asset:
+$ lim get asset app.apk --versions
 `,
+	ValidArgsFunction: completion.FirstArg(completion.Assets),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var id string
 		if len(args) > 0 {
@@ -60,7 +74,36 @@ $ lim get asset <ID>
 		}
 		var data [][]string
 		table := tablewriter.NewWriter(cmd.OutOrStdout())
-		lim := cmd.Context().Value("lim").(limrun.Client)
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if listVersions {
+			if id == "" {
+				return fmt.Errorf("asset name is required to list versions")
+			}
+			idx, err := assets.LoadIndex(cmd.Context(), lim, id)
+			if err != nil {
+				return err
+			}
+			table.Header([]string{"Version", "ID", "MD5", "Tags", "Labels", "Metadata", "Created"})
+			for i := len(idx.Versions) - 1; i >= 0; i-- {
+				v := idx.Versions[i]
+				data = append(data, []string{
+					v.Version,
+					v.AssetID,
+					v.Md5,
+					strings.Join(v.Tags, ","),
+					formatKeyValues(v.Labels),
+					formatKeyValues(v.Metadata),
+					v.CreatedAt.Local().Format(time.DateTime),
+				})
+			}
+			if err := table.Bulk(data); err != nil {
+				return err
+			}
+			return table.Render()
+		}
 		var instances []limrun.Asset
 		if id == "" {
 			params := limrun.AssetListParams{
@@ -76,18 +119,31 @@ $ lim get asset <ID>
 					if err := config.Login(cmd.Context()); err != nil {
 						return err
 					}
-					fmt.Println("You are logged in now")
+					fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 					return nil
 				}
 				return fmt.Errorf("failed to list assets: %w", err)
 			}
-			instances = *fetched
-		} else {
-			fetched, err := lim.Assets.Get(cmd.Context(), id, limrun.AssetGetParams{})
+			for _, ass := range *fetched {
+				if !assets.IsIndex(ass.Name) {
+					instances = append(instances, ass)
+				}
+			}
+		} else if _, err := typeid.Parse(id); err == nil {
+			fetched, err := lim.Assets.Get(cmd.Context(), id, limrun.AssetGetParams{
+				IncludeDownloadURL: param.NewOpt(includeDownloadUrl),
+				IncludeUploadURL:   param.NewOpt(includeUploadUrl),
+			})
 			if err != nil {
 				return fmt.Errorf("failed to get asset: %w", err)
 			}
 			instances = []limrun.Asset{*fetched}
+		} else {
+			ass, err := assets.InteractiveResolver(false).Resolve(cmd.Context(), lim, id)
+			if err != nil {
+				return err
+			}
+			instances = []limrun.Asset{ass}
 		}
 		data = make([][]string, len(instances))
 		for i, instance := range instances {
@@ -117,3 +173,13 @@ $ lim get asset <ID>
 		return table.Render()
 	},
 }
+
+// formatKeyValues formats the map as sorted k=v pairs.
+func formatKeyValues(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
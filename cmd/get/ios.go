@@ -19,6 +19,7 @@ package get
 import (
 	"fmt"
 	"github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
 	"github.com/olekukonko/tablewriter"
@@ -38,6 +39,7 @@ $ lim get ios
 Get a specific iOS instance:
 $ lim get ios <ID>
 `,
+	ValidArgsFunction: completion.FirstArg(completion.IOSInstances),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		var id string
 		if len(args) > 1 {
@@ -45,7 +47,10 @@ $ lim get ios <ID>
 		}
 		var data [][]string
 		table := tablewriter.NewWriter(cmd.OutOrStdout())
-		lim := cmd.Context().Value("lim").(limrun.Client)
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
 		table.Header([]string{"ID", "Name", "Region", "State"})
 		var instances []limrun.IosInstance
 		if id == "" {
@@ -57,7 +62,7 @@ $ lim get ios <ID>
 					if err := config.Login(cmd.Context()); err != nil {
 						return err
 					}
-					fmt.Println("You are logged in now")
+					fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 					return nil
 				}
 				return fmt.Errorf("failed to list ios instances: %w", err)
@@ -0,0 +1,56 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/limrun-inc/lim/cmd/install"
+	"github.com/spf13/cobra"
+)
+
+// InstallCmd represents the install command
+var InstallCmd = &cobra.Command{
+	Use:   "install [ID] [file or asset name]...",
+	Short: "Installs apps on a running instance.",
+	Long: `Examples:
+
+$ lim install <ID> app.apk
+$ lim install <ID> base.apk,split_config.arm64_v8a.apk
+`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		switch strings.Split(id, "_")[0] {
+		case "android":
+			return install.AndroidCmd.RunE(cmd, args)
+		case "ios":
+			return install.IOSCmd.RunE(cmd, args)
+		default:
+			return fmt.Errorf("invalid id: %s", id)
+		}
+	},
+}
+
+func init() {
+	InstallCmd.Flags().AddFlag(install.AndroidCmd.PersistentFlags().Lookup("adb-path"))
+	InstallCmd.Flags().AddFlag(install.AndroidCmd.PersistentFlags().Lookup("bundletool-path"))
+	InstallCmd.AddCommand(install.AndroidCmd)
+	InstallCmd.AddCommand(install.IOSCmd)
+	RootCmd.AddCommand(InstallCmd)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/limrun-inc/lim/adb"
+	"github.com/limrun-inc/lim/artifact"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/spf13/cobra"
+)
+
+var (
+	adbPath        string
+	bundletoolPath string
+)
+
+func init() {
+	AndroidCmd.PersistentFlags().StringVar(&adbPath, "adb-path", "adb", "Optional path to the adb binary, defaults to `adb`")
+	AndroidCmd.PersistentFlags().StringVar(&bundletoolPath, "bundletool-path", "bundletool", "Optional path to the bundletool binary or jar file used to convert app bundles, defaults to `bundletool`")
+}
+
+// AndroidCmd represents the install command for Android
+var AndroidCmd = &cobra.Command{
+	Use:   "android [ID] [file or asset name]...",
+	Short: "Installs apps on a running Android instance.",
+	Long: `Each argument is either a local APK file or the name of an uploaded asset.
+Files of a split APK group that will be installed together should be separated by comma.
+App bundles (.aab) are converted into split APKs with bundletool and XAPK archives
+are extracted. Local files are uploaded to the asset storage first if they are not
+there already and installed from there like the assets.
+
+Examples:
+
+$ lim install android <ID> app.apk
+$ lim install android <ID> base.apk,split_config.arm64_v8a.apk other-asset.apk
+$ lim install android <ID> app-release.aab
+`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completion.ThenFiles(completion.AndroidInstances),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		i, err := lim.AndroidInstances.Get(cmd.Context(), id)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to get Android instance %s: %w", id, err)
+		}
+		tmpDir, err := os.MkdirTemp("", "lim-install-")
+		if err != nil {
+			return fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+		d, err := adb.Connect(i, adbPath)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		var failed int
+		for _, group := range args[1:] {
+			if err := installAndroidApp(cmd, lim, d, group, tmpDir); err != nil {
+				failed++
+				fmt.Fprintf(cmd.OutOrStdout(), "Failed to install %s: %s\n", group, err)
+				continue
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Installed %s\n", group)
+		}
+		if failed > 0 {
+			return fmt.Errorf("failed to install %d of %d app(s)", failed, len(args)-1)
+		}
+		return nil
+	},
+}
+
+func installAndroidApp(cmd *cobra.Command, lim limrun.Client, d *adb.Device, group, tmpDir string) error {
+	var refs []string
+	for _, n := range splitGroup(group) {
+		if !isLocalFile(n) {
+			refs = append(refs, n)
+			continue
+		}
+		a, err := artifact.Prepare(cmd.Context(), n, artifact.Options{
+			Dir:            tmpDir,
+			BundletoolPath: bundletoolPath,
+		})
+		if err != nil {
+			return err
+		}
+		uploaded, err := assets.UploadArtifact(cmd.Context(), lim, a, false)
+		if err != nil {
+			return err
+		}
+		for _, ass := range uploaded {
+			refs = append(refs, ass.ID)
+		}
+	}
+	var paths []string
+	for _, ref := range refs {
+		ass, err := findAsset(cmd.Context(), lim, ref)
+		if err != nil {
+			return err
+		}
+		p := filepath.Join(tmpDir, ass.ID+"-"+filepath.Base(ass.Name))
+		if err := assets.Download(cmd.Context(), lim, *ass, p, nil); err != nil {
+			return err
+		}
+		paths = append(paths, p)
+	}
+	return d.Install(cmd.Context(), paths...)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/limrun-inc/lim/assets"
+
+	limrun "github.com/limrun-inc/go-sdk"
+)
+
+// splitGroup returns the files or asset names of an app that are separated
+// by comma.
+func splitGroup(group string) []string {
+	var arr []string
+	for _, n := range strings.Split(group, ",") {
+		if n == "" {
+			continue
+		}
+		arr = append(arr, n)
+	}
+	return arr
+}
+
+// isLocalFile returns whether the given argument refers to a file on disk
+// rather than an asset name.
+func isLocalFile(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// findAsset returns the asset with the given ID, exact name, name@version
+// or name:tag along with its download URL.
+func findAsset(ctx context.Context, lim limrun.Client, ref string) (*limrun.Asset, error) {
+	ass, err := assets.InteractiveResolver(false).Resolve(ctx, lim, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &ass, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package install
+
+import (
+	"fmt"
+
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+
+	"github.com/spf13/cobra"
+)
+
+// IOSCmd represents the install command for iOS
+var IOSCmd = &cobra.Command{
+	Use:   "ios [ID] [file or asset name]...",
+	Short: "Installs apps on a running iOS instance.",
+	Long: `Installing apps on running iOS instances is not supported yet since the API
+doesn't document a way to do it. The command fails with the unsupported exit code
+until it does.
+`,
+	Args:              cobra.MinimumNArgs(2),
+	ValidArgsFunction: completion.ThenFiles(completion.IOSInstances),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		i, err := lim.IosInstances.Get(cmd.Context(), id)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to get iOS instance %s: %w", id, err)
+		}
+		return errors.Newf(errors.KindUnsupported, "installing apps on running iOS instances is not supported yet, %s is left as is", i.Metadata.ID)
+	},
+}
@@ -28,7 +28,7 @@ var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Remove the API key that lim uses to talk with Limrun.",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Println("logout called")
+		fmt.Fprintln(cmd.OutOrStdout(), "logout called")
 		viper.Set("api-key", "")
 		return viper.WriteConfig()
 	},
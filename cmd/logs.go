@@ -0,0 +1,217 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/adb"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+)
+
+var (
+	logsFollow    bool
+	logsSince     time.Duration
+	logsGrep      string
+	logsPackage   string
+	logsFormat    string
+	logsSave      string
+	logsPush      bool
+	logsAssetName string
+	logsADBPath   string
+)
+
+func init() {
+	LogsCmd.PersistentFlags().BoolVarP(&logsFollow, "follow", "f", false, "Keep streaming new log lines until Ctrl+C is pressed.")
+	LogsCmd.PersistentFlags().DurationVar(&logsSince, "since", 0, "Only show logs newer than the given duration, e.g. 5m.")
+	LogsCmd.PersistentFlags().StringVar(&logsGrep, "grep", "", "Only show lines matching the given regular expression.")
+	LogsCmd.PersistentFlags().StringVar(&logsPackage, "package", "", "Only show logs of the given app package, e.g. com.example.")
+	LogsCmd.PersistentFlags().StringVar(&logsFormat, "format", "text", "Output format, one of text or json. JSON is printed one object per line.")
+	LogsCmd.PersistentFlags().StringVar(&logsSave, "save", "", "Also save the logs to the given file.")
+	LogsCmd.PersistentFlags().BoolVar(&logsPush, "push", false, "Upload the logs to the asset storage when the command exits.")
+	LogsCmd.PersistentFlags().StringVarP(&logsAssetName, "name", "n", "", "Name of the asset when pushed. Defaults to file name.")
+	LogsCmd.PersistentFlags().StringVar(&logsADBPath, "adb-path", "adb", "Optional path to the adb binary, defaults to `adb`")
+	RootCmd.AddCommand(LogsCmd)
+}
+
+var logPriorityColors = map[string]*color.Color{
+	"V": color.New(color.FgHiBlack),
+	"D": color.New(color.FgBlue),
+	"I": color.New(color.FgGreen),
+	"W": color.New(color.FgYellow),
+	"E": color.New(color.FgRed),
+	"F": color.New(color.FgRed, color.Bold),
+}
+
+// LogsCmd represents the logs command
+var LogsCmd = &cobra.Command{
+	Use:   "logs [ID]",
+	Short: "Prints the logcat of an Android instance.",
+	Long: `Only Android instances are supported. iOS instances are not since their
+endpoint doesn't expose the system log.
+
+Examples:
+
+$ lim logs <ID>
+$ lim logs <ID> -f --since 5m --package com.example --grep Exception
+$ lim logs <ID> --format json --save logs.jsonl --push
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.AndroidInstances),
+	Annotations:       map[string]string{config.AnnotationSession: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		if logsFormat != "text" && logsFormat != "json" {
+			return fmt.Errorf("invalid format %q, must be one of text or json", logsFormat)
+		}
+		var grep *regexp.Regexp
+		if logsGrep != "" {
+			var err error
+			if grep, err = regexp.Compile(logsGrep); err != nil {
+				return fmt.Errorf("invalid grep pattern: %w", err)
+			}
+		}
+		switch strings.Split(id, "_")[0] {
+		case "android":
+		case "ios":
+			return errors.Newf(errors.KindInvalidArgument, "logs are only supported for Android instances, %s is an iOS instance", id)
+		default:
+			return fmt.Errorf("invalid id: %s", id)
+		}
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		getCtx, cancelGet := config.WithTimeout(cmd.Context())
+		defer cancelGet()
+		i, err := lim.AndroidInstances.Get(getCtx, id)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to get Android instance %s: %w", id, err)
+		}
+		savePath := logsSave
+		if savePath == "" && logsPush {
+			ext := "log"
+			if logsFormat == "json" {
+				ext = "jsonl"
+			}
+			savePath = fmt.Sprintf("%s-%s.%s", id, time.Now().Format("20060102-150405"), ext)
+		}
+		var save io.Writer = io.Discard
+		if savePath != "" {
+			f, err := os.Create(savePath)
+			if err != nil {
+				return fmt.Errorf("failed to create file %s: %w", savePath, err)
+			}
+			defer f.Close()
+			save = f
+		}
+		d, err := adb.Connect(i, logsADBPath)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer cancel()
+		opts := adb.LogcatOptions{
+			Follow:  logsFollow,
+			Package: logsPackage,
+		}
+		if logsSince > 0 {
+			opts.Since = time.Now().Add(-logsSince)
+		}
+		c, err := d.Logcat(ctx, opts)
+		if err != nil {
+			return err
+		}
+		stdout, err := c.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		c.Stderr = cmd.ErrOrStderr()
+		if err := c.Start(); err != nil {
+			return fmt.Errorf("failed to start logcat: %w", err)
+		}
+		out := cmd.OutOrStdout()
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if grep != nil && !grep.MatchString(line) {
+				continue
+			}
+			if logsFormat == "json" {
+				b, err := json.Marshal(adb.ParseLogLine(line))
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(out, string(b))
+				_, _ = fmt.Fprintln(save, string(b))
+				continue
+			}
+			entry := adb.ParseLogLine(line)
+			if c, ok := logPriorityColors[entry.Priority]; ok {
+				_, _ = c.Fprintln(out, line)
+			} else {
+				_, _ = fmt.Fprintln(out, line)
+			}
+			_, _ = fmt.Fprintln(save, line)
+		}
+		if err := scanner.Err(); err != nil {
+			// Stop logcat since nobody reads its output anymore.
+			cancel()
+			_ = c.Wait()
+			return fmt.Errorf("failed to read logcat output: %w", err)
+		}
+		if err := c.Wait(); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("logcat failed: %w", err)
+		}
+		if savePath != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Saved logs to %s\n", savePath)
+		}
+		if logsPush {
+			ass, err := assets.Upload(context.WithoutCancel(ctx), lim, savePath, logsAssetName)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "ID: %s\n", ass.ID)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/pem"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// useTLS serves the fake API over HTTPS and returns the path of the PEM
+// file of its CA.
+func (h *harness) useTLS() string {
+	h.t.Helper()
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.handler.ServeHTTP(w, r)
+	}))
+	// Failed handshakes are expected and would end up in the output of
+	// the next command through the default logger.
+	ts.Config.ErrorLog = log.New(io.Discard, "", 0)
+	ts.StartTLS()
+	h.t.Cleanup(ts.Close)
+	h.url = ts.URL
+	caFile := filepath.Join(h.dir, "ca.pem")
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	if err := os.WriteFile(caFile, b, 0600); err != nil {
+		h.t.Fatal(err)
+	}
+	return caFile
+}
+
+// connectProxy returns the URL of a proxy that tunnels CONNECT requests and
+// counts them.
+func connectProxy(t *testing.T) (string, *atomic.Int32) {
+	var n atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		n.Add(1)
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		w.WriteHeader(http.StatusOK)
+		conn, buf, err := http.NewResponseController(w).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		go func() {
+			_, _ = io.Copy(upstream, buf)
+		}()
+		_, _ = io.Copy(conn, upstream)
+	}))
+	t.Cleanup(ts.Close)
+	return ts.URL, &n
+}
+
+func TestCAFile(t *testing.T) {
+	h := newHarness(t)
+	caFile := h.useTLS()
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	if err := h.runWithFlags("get", "asset", "--max-retries", "0"); err == nil {
+		t.Error("the certificate of the server was trusted without --ca-file")
+	}
+	if err := h.runWithFlags("get", "asset", "--ca-file", caFile); err != nil {
+		t.Errorf("the certificate of the server was not trusted with --ca-file: %s", err)
+	}
+	h.assertGolden("ca_file")
+}
+
+func TestHTTPSProxy(t *testing.T) {
+	h := newHarness(t)
+	caFile := h.useTLS()
+	proxy, connects := connectProxy(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	if err := h.runWithFlags("get", "asset", "--ca-file", caFile, "--https-proxy", proxy); err != nil {
+		t.Fatal(err)
+	}
+	if got := connects.Load(); got != 1 {
+		t.Errorf("proxy got %d connections, want 1", got)
+	}
+	if err := h.runWithFlags("get", "asset", "--ca-file", caFile, "--https-proxy", proxy, "--no-proxy", "example.com,127.0.0.0/8"); err != nil {
+		t.Fatal(err)
+	}
+	if got := connects.Load(); got != 1 {
+		t.Errorf("proxy got %d connections with --no-proxy, want 1", got)
+	}
+}
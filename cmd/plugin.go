@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+	"github.com/limrun-inc/lim/plugin"
+)
+
+// PluginCmd represents the plugin command
+var PluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "Manage the plugins that extend lim with commands.",
+	Long: `Any executable on PATH whose name starts with lim- is a plugin, e.g. lim-smoke
+is run as lim smoke and lim-smoke-android as lim smoke android. Built-in
+commands take precedence over plugins with the same name.
+
+Plugins get the arguments after their name and these environment variables:
+
+  LIM_API_ENDPOINT  the API endpoint lim would use
+  LIM_API_KEY       the API key lim would use
+  LIM_CONFIG_FILE   the config file lim read, if any
+
+Since lim reads the same variables, plugins can run lim commands against the
+same endpoint with the same key.`,
+	Run: func(cmd *cobra.Command, args []string) {},
+}
+
+// pluginListCmd lists the plugins
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists the plugins found on PATH.",
+	Long: `Plugins that can't be run because a built-in command or another plugin
+earlier on PATH has the same name are listed with what shadows them.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := plugin.List()
+		if len(plugins) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No plugins found on PATH")
+			return nil
+		}
+		table := tablewriter.NewWriter(cmd.OutOrStdout())
+		table.Header([]string{"Command", "Path", "Shadowed By"})
+		data := make([][]string, len(plugins))
+		for i, p := range plugins {
+			shadowedBy := p.ShadowedBy
+			if c, ok := builtinCommand(p.Name); ok {
+				shadowedBy = "built-in " + c.CommandPath()
+			}
+			data[i] = []string{"lim " + p.Name, p.Path, shadowedBy}
+		}
+		if err := table.Bulk(data); err != nil {
+			return err
+		}
+		return table.Render()
+	},
+}
+
+func init() {
+	PluginCmd.AddCommand(pluginListCmd)
+	RootCmd.AddCommand(PluginCmd)
+}
+
+// builtinCommand returns the top level command with the given name or
+// alias.
+func builtinCommand(name string) (*cobra.Command, bool) {
+	RootCmd.InitDefaultHelpCmd()
+	RootCmd.InitDefaultCompletionCmd()
+	for _, c := range RootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// findPlugin returns the plugin to run and its arguments if the arguments
+// start with a name that isn't a built-in command.
+func findPlugin(args []string) (string, []string, bool) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return "", nil, false
+	}
+	if _, ok := builtinCommand(args[0]); ok {
+		return "", nil, false
+	}
+	return plugin.Find(args)
+}
+
+// runPlugin runs the plugin with the API endpoint and key resolved from the
+// config and the environment. The exit code of the plugin becomes the exit
+// code of lim.
+func runPlugin(ctx context.Context, path string, args []string) error {
+	if err := initializeConfig(RootCmd); err != nil {
+		return err
+	}
+	c := exec.CommandContext(ctx, path, args...)
+	c.Stdin = RootCmd.InOrStdin()
+	c.Stdout = RootCmd.OutOrStdout()
+	c.Stderr = RootCmd.ErrOrStderr()
+	c.Env = append(os.Environ(),
+		"LIM_API_ENDPOINT="+viper.GetString(config.ConfigKeyAPIEndpoint),
+		"LIM_API_KEY="+viper.GetString(config.ConfigKeyAPIKey),
+		"LIM_CONFIG_FILE="+viper.ConfigFileUsed(),
+	)
+	if err := c.Start(); err != nil {
+		return fmt.Errorf("failed to run plugin %s: %w", path, err)
+	}
+	// Ctrl+C reaches the plugin from the terminal, so lim only needs to
+	// outlive it. A termination signal is passed on.
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case sig := <-sigChan:
+				if sig == syscall.SIGTERM {
+					_ = c.Process.Signal(sig)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	err := c.Wait()
+	var exitErr *exec.ExitError
+	if goerrors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			code = 1
+		}
+		return &errors.ExitError{Code: code}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run plugin %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+// writePlugin creates an executable shell script in the directory.
+func (h *harness) writePlugin(dir, name, script string) {
+	h.t.Helper()
+	h.writeFile(filepath.Join(dir, name), "#!/bin/sh\n"+script)
+	if err := os.Chmod(filepath.Join(dir, name), 0755); err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	h := newHarness(t)
+	first := filepath.Join(h.dir, "bin")
+	second := filepath.Join(h.dir, "tools")
+	h.writePlugin(first, "lim-smoke", `echo "lim-smoke $*"
+echo "endpoint=$LIM_API_ENDPOINT key=$LIM_API_KEY config=$LIM_CONFIG_FILE"
+`)
+	h.writePlugin(first, "lim-smoke-android", `echo "lim-smoke-android $*"`)
+	h.writePlugin(first, "lim-fail", `echo "something went wrong" >&2
+exit 42
+`)
+	h.writePlugin(second, "lim-smoke", `echo "never run"`)
+	h.writePlugin(second, "lim-get", `echo "never run"`)
+	h.writeFile(filepath.Join(second, "lim-notes.txt"), "not executable")
+	// PATH is relative to the working directory so that the width of the
+	// table of plugins doesn't depend on the temporary directory.
+	t.Setenv("PATH", filepath.Join("..", "bin")+string(os.PathListSeparator)+filepath.Join("..", "tools"))
+	h.writeFile(filepath.Join(h.dir, "home", ".lim", "config.yaml"), "api-endpoint: "+h.url+"\napi-key: test\n")
+
+	if err := h.run("smoke", "--fast", "a b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("smoke", "android", "--rm"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("fail"); errors.ExitCode(err) != 42 {
+		t.Errorf("got exit code %d, want the one of the plugin", errors.ExitCode(err))
+	}
+	if err := h.run("plugin", "list"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("unknown"); errors.KindOf(err) != errors.KindInvalidArgument {
+		t.Errorf("got %v, want an invalid argument error", err)
+	}
+	h.assertGolden("plugins")
+}
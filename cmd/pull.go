@@ -17,108 +17,206 @@ limitations under the License.
 package cmd
 
 import (
+	goerrors "errors"
 	"fmt"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
 	"github.com/schollz/progressbar/v3"
-	"io"
-	"net/http"
-	"os"
 	"path/filepath"
+	"strings"
 
 	limrun "github.com/limrun-inc/go-sdk"
 	"github.com/limrun-inc/go-sdk/packages/param"
 	"github.com/spf13/cobra"
-	"go.jetify.com/typeid/v2"
 )
 
 var (
-	downloadAssetName string
-	outDir            string
+	downloadAssetName   string
+	downloadNameFilter  string
+	downloadConcurrency int
+	downloadLatest      bool
+	outDir              string
 )
 
 func init() {
 	PullCmd.PersistentFlags().StringVarP(&downloadAssetName, "name", "n", "", "Name of the asset.")
-	PullCmd.PersistentFlags().StringVarP(&outDir, "output", "o", ".", "Output directory. Defaults to current directory.")
+	PullCmd.PersistentFlags().StringVar(&downloadNameFilter, "name-filter", "", "Pull all assets whose name contains the given text.")
+	PullCmd.PersistentFlags().BoolVar(&downloadLatest, "latest", false, "Pick the most recently created asset when several assets have the exact name.")
+	PullCmd.PersistentFlags().IntVar(&downloadConcurrency, "concurrency", 4, "Number of assets to download in parallel.")
+	PullCmd.PersistentFlags().StringVarP(&outDir, "output", "o", ".", "Output directory, or - to write a single asset to stdout. Defaults to current directory.")
 	RootCmd.AddCommand(PullCmd)
 }
 
 // PullCmd represents the push command
 var PullCmd = &cobra.Command{
-	Use:  "pull [ID or Name]",
-	Args: cobra.ExactArgs(1),
+	Use:   "pull [ID or Name]...",
+	Short: "Downloads assets from the asset storage.",
+	Long: `Assets are matched by their exact name. If there is no exact match but several
+assets contain the name, you're asked to pick one on a terminal. Otherwise the
+command fails listing the candidates unless --latest is given.
+
+Pulled assets are kept in the local cache, see lim cache, and pulling the same
+content again copies it from there.
+
+Examples:
+
+$ lim pull app.apk
+$ lim pull <ID> other.apk -o ./build
+$ lim pull --name-filter build-42/ --concurrency 8
+$ lim pull app.apk -o - | sha256sum
+`,
+	ValidArgsFunction: completion.Assets,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		lim := cmd.Context().Value("lim").(limrun.Client)
-		var id string
-		_, err := typeid.Parse(args[0])
+		lim, err := config.ClientFromContext(cmd.Context())
 		if err != nil {
-			downloadAssetName = args[0]
-		} else {
-			id = args[0]
+			return err
 		}
-		var ass limrun.Asset
-		switch {
-		case id != "":
-			fetched, err := lim.Assets.Get(cmd.Context(), id, limrun.AssetGetParams{
-				IncludeDownloadURL: param.NewOpt(true),
-			})
+		refs := args
+		if downloadAssetName != "" {
+			refs = append(refs, downloadAssetName)
+		}
+		if len(refs) == 0 && downloadNameFilter == "" {
+			return errors.Newf(errors.KindInvalidArgument, "no asset id or name specified")
+		}
+		resolver := assets.InteractiveResolver(downloadLatest)
+		var toPull []limrun.Asset
+		for _, ref := range refs {
+			ass, err := resolver.Resolve(cmd.Context(), lim, ref)
 			if err != nil {
 				if errors.IsUnauthenticated(err) {
 					if err := config.Login(cmd.Context()); err != nil {
 						return err
 					}
-					fmt.Println("You are logged in now")
+					fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 					return nil
 				}
-				return fmt.Errorf("failed to get asset: %w", err)
+				return err
 			}
-			ass = *fetched
-		case downloadAssetName != "":
+			ass.Name = localName(ref, ass)
+			toPull = append(toPull, ass)
+		}
+		if downloadNameFilter != "" {
 			fetched, err := lim.Assets.List(cmd.Context(), limrun.AssetListParams{
-				NameFilter:         param.NewOpt(downloadAssetName),
+				NameFilter:         param.NewOpt(downloadNameFilter),
 				IncludeDownloadURL: param.NewOpt(true),
 			})
 			if err != nil {
-				return fmt.Errorf("failed to get asset with name %s: %w", downloadAssetName, err)
+				return fmt.Errorf("failed to list assets: %w", err)
 			}
 			if len(*fetched) == 0 {
-				return fmt.Errorf("asset with name %s not found", downloadAssetName)
+				return errors.Newf(errors.KindNotFound, "no assets match %s", downloadNameFilter)
+			}
+			for _, ass := range *fetched {
+				if !assets.IsIndex(ass.Name) {
+					toPull = append(toPull, ass)
+				}
 			}
-			ass = (*fetched)[0]
-		default:
-			return fmt.Errorf("no asset id or name specified")
-		}
-		fullPath, err := filepath.Abs(filepath.Join(outDir, ass.Name))
-		if err != nil {
-			return fmt.Errorf("failed to get absolute path: %w", err)
 		}
-		resp, err := http.Get(ass.SignedDownloadURL)
-		if err != nil {
-			return fmt.Errorf("failed to download file: %w", err)
+		seen := map[string]bool{}
+		unique := toPull[:0]
+		for _, ass := range toPull {
+			if !seen[ass.ID] {
+				seen[ass.ID] = true
+				unique = append(unique, ass)
+			}
 		}
-		defer resp.Body.Close()
-		if resp.StatusCode != http.StatusOK {
-			b, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("failed to download file: %s", string(b))
+		toPull = unique
+		// Assets may share a name, in which case they would be downloaded
+		// into the same file at the same time.
+		if outDir != "-" {
+			targets := map[string]string{}
+			for _, ass := range toPull {
+				fullPath, err := assetPath(outDir, ass.Name)
+				if err != nil {
+					return err
+				}
+				if other, ok := targets[fullPath]; ok {
+					return errors.Newf(errors.KindConflict, "assets %s and %s would both be pulled to %s, pull them one at a time into different directories", other, ass.ID, fullPath)
+				}
+				targets[fullPath] = ass.ID
+			}
 		}
-		fmt.Printf("Pulling to %s\n", fullPath)
-		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
-			return fmt.Errorf("failed to create output directory: %w", err)
+		if outDir == "-" {
+			if len(toPull) != 1 {
+				return fmt.Errorf("only a single asset can be written to stdout, got %d", len(toPull))
+			}
+			return assets.Stream(cmd.Context(), lim, toPull[0], cmd.OutOrStdout())
 		}
-		file, err := os.Create(fullPath)
-		if err != nil {
-			return fmt.Errorf("failed to create file %s: %w", fullPath, err)
+		if len(toPull) == 1 {
+			fullPath, err := assetPath(outDir, toPull[0].Name)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Pulling to %s\n", fullPath)
+			if err := assets.Download(cmd.Context(), lim, toPull[0], fullPath, nil); err != nil {
+				if goerrors.Is(err, assets.ErrUpToDate) {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s is up to date\n", fullPath)
+					return nil
+				}
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Done!\n")
+			return nil
 		}
-		defer file.Close()
 		bar := progressbar.DefaultBytes(
-			resp.ContentLength,
-			"",
+			-1,
+			fmt.Sprintf("%d asset(s)", len(toPull)),
 		)
-		_, err = io.Copy(io.MultiWriter(file, bar), resp.Body)
+		results := make([]string, len(toPull))
+		err = parallel(downloadConcurrency, len(toPull), func(i int) error {
+			fullPath, err := assetPath(outDir, toPull[i].Name)
+			if err != nil {
+				return err
+			}
+			err = assets.Download(cmd.Context(), lim, toPull[i], fullPath, bar)
+			switch {
+			case goerrors.Is(err, assets.ErrUpToDate):
+				results[i] = fmt.Sprintf("%s is up to date", fullPath)
+			case err != nil:
+				return fmt.Errorf("failed to pull %s: %w", toPull[i].Name, err)
+			default:
+				results[i] = fmt.Sprintf("Pulled %s", fullPath)
+			}
+			return nil
+		})
+		_ = bar.Finish()
+		fmt.Fprintln(cmd.OutOrStdout())
+		for _, r := range results {
+			if r != "" {
+				fmt.Fprintln(cmd.OutOrStdout(), r)
+			}
+		}
 		if err != nil {
-			return fmt.Errorf("failed to write file %s: %w", fullPath, err)
+			return err
 		}
-		fmt.Printf("Done!\n")
+		fmt.Fprintf(cmd.OutOrStdout(), "Done!\n")
 		return nil
 	},
 }
+
+// localName returns the name an asset resolved from ref is saved as.
+// Versions are saved under the name of the asset they belong to.
+func localName(ref string, ass limrun.Asset) string {
+	if name, _, ok := assets.SplitVersionRef(ref); ok && strings.HasPrefix(ass.Name, name+"@") {
+		return name
+	}
+	if strings.HasPrefix(ass.Name, ref+"@") {
+		return ref
+	}
+	return ass.Name
+}
+
+// assetPath returns the absolute path the asset with the given name is saved
+// to in dir. Names may contain slashes but must not point outside of dir.
+func assetPath(dir, name string) (string, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("asset name %s is not a valid local path", name)
+	}
+	fullPath, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	return fullPath, nil
+}
@@ -18,59 +18,185 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/olekukonko/tablewriter"
 	"github.com/schollz/progressbar/v3"
 	"github.com/spf13/cobra"
 
 	limrun "github.com/limrun-inc/go-sdk"
 	"github.com/limrun-inc/go-sdk/packages/param"
 
+	"github.com/limrun-inc/lim/assets"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
 )
 
 var (
-	uploadAssetName string
+	uploadAssetName   string
+	uploadPrefix      string
+	uploadInclude     []string
+	uploadExclude     []string
+	uploadConcurrency int
+	uploadVersion     string
+	uploadTags        []string
+	uploadLabels      []string
+	uploadMetadata    []string
 )
 
 func init() {
-	PushCmd.PersistentFlags().StringVarP(&uploadAssetName, "name", "n", "", "Name of the asset. Defaults to file name.")
+	PushCmd.PersistentFlags().StringVarP(&uploadAssetName, "name", "n", "", "Name of the asset. Defaults to file name. Only valid for a single file and required when reading from stdin with -.")
+	PushCmd.PersistentFlags().StringVar(&uploadPrefix, "prefix", "", "Prefix to prepend to the names of all uploaded assets.")
+	PushCmd.PersistentFlags().StringArrayVar(&uploadInclude, "include", []string{}, "Only upload files in directories whose relative path matches the glob, e.g. '**/*.apk'. Can be repeated.")
+	PushCmd.PersistentFlags().StringArrayVar(&uploadExclude, "exclude", []string{}, "Skip files in directories whose relative path matches the glob. Can be repeated.")
+	PushCmd.PersistentFlags().IntVar(&uploadConcurrency, "concurrency", 4, "Number of files to upload in parallel.")
+	PushCmd.PersistentFlags().StringVar(&uploadVersion, "version", "", "Upload as a new version of the asset with the given version. Defaults to the next number if --tag, --label or --metadata is given.")
+	PushCmd.PersistentFlags().StringArrayVar(&uploadTags, "tag", []string{}, "Tag to point to the uploaded version, e.g. stable. A tag points to one version at a time. Can be repeated.")
+	PushCmd.PersistentFlags().StringArrayVar(&uploadLabels, "label", []string{}, "Label of the uploaded version in k=v format. Can be repeated.")
+	PushCmd.PersistentFlags().StringArrayVar(&uploadMetadata, "metadata", []string{}, "Free-form metadata of the uploaded version in k=v format. Can be repeated.")
 	RootCmd.AddCommand(PushCmd)
 }
 
+// fileToUpload is a local file and the asset name it will be uploaded as.
+type fileToUpload struct {
+	Path string
+	Name string
+	Size int64
+}
+
 // PushCmd represents the upload asset command
 var PushCmd = &cobra.Command{
-	Use:  "push [file path]",
-	Args: cobra.ExactArgs(1),
+	Use:   "push [file or directory path]...",
+	Short: "Uploads files to the asset storage.",
+	Long: `Directories are uploaded recursively and the relative path of each file is used
+as its asset name.
+
+With --version, --tag, --label or --metadata, the file is stored as a new
+version of the asset instead of replacing it. Versions can be referred to as
+name@version or name:tag in pull, get and --install-asset.
+
+Examples:
+
+$ lim push app.apk
+$ lim push app.apk fixtures/ --prefix build-42/
+$ lim push ./build --include '**/*.apk' --exclude '**/*-unsigned.apk'
+$ lim push app.apk --tag nightly --label branch=main --metadata commit=abc123
+$ ./build.sh | lim push - --name app.apk
+`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		lim := cmd.Context().Value("lim").(limrun.Client)
-		f, err := os.Stat(args[0])
+		lim, err := config.ClientFromContext(cmd.Context())
 		if err != nil {
 			return err
 		}
-		name := filepath.Base(args[0])
+		var files []fileToUpload
+		if slices.Contains(args, "-") {
+			if len(args) != 1 || uploadAssetName == "" {
+				return errors.Newf(errors.KindInvalidArgument, "reading from stdin requires - to be the only argument and --name to be set")
+			}
+			f, err := spoolStdin(cmd.InOrStdin())
+			if err != nil {
+				return err
+			}
+			defer os.Remove(f.Path)
+			files = append(files, f)
+		} else {
+			if files, err = collectFilesToUpload(args); err != nil {
+				return err
+			}
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no files to upload")
+		}
 		if uploadAssetName != "" {
-			name = uploadAssetName
+			if len(files) > 1 {
+				return errors.Newf(errors.KindInvalidArgument, "--name can only be used when uploading a single file")
+			}
+			files[0].Name = uploadAssetName
+		}
+		labels, err := assets.ParseKeyValues(uploadLabels)
+		if err != nil {
+			return err
+		}
+		metadata, err := assets.ParseKeyValues(uploadMetadata)
+		if err != nil {
+			return err
+		}
+		versioned := uploadVersion != "" || len(uploadTags) > 0 || len(labels) > 0 || len(metadata) > 0
+		var total int64
+		for _, f := range files {
+			total += f.Size
+		}
+		if len(files) == 1 {
+			fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\n", files[0].Name)
 		}
-		fmt.Printf("Name: %s\n", name)
 		bar := progressbar.DefaultBytes(
-			f.Size(),
-			"",
+			total,
+			fmt.Sprintf("%d file(s)", len(files)),
 		)
-		params := limrun.AssetGetOrUploadParams{
-			Path:           args[0],
-			ProgressWriter: bar,
-			Name:           param.NewOpt(name),
-		}
-		ass, err := lim.Assets.GetOrUpload(cmd.Context(), params)
+		uploaded := make([]*limrun.AssetGetOrNewResponse, len(files))
+		versions := make([]string, len(files))
+		err = parallel(uploadConcurrency, len(files), func(i int) error {
+			name := files[i].Name
+			var idx *assets.Index
+			if versioned {
+				var err error
+				if idx, err = assets.LoadIndex(cmd.Context(), lim, name); err != nil {
+					return err
+				}
+				versions[i] = uploadVersion
+				if versions[i] == "" {
+					if versions[i], err = assets.FreeVersion(cmd.Context(), lim, idx); err != nil {
+						return err
+					}
+				}
+				name = assets.VersionedName(name, versions[i])
+			}
+			w := &countingWriter{w: bar}
+			ass, err := lim.Assets.GetOrUpload(cmd.Context(), limrun.AssetGetOrUploadParams{
+				Path:           files[i].Path,
+				ProgressWriter: w,
+				Name:           param.NewOpt(name),
+			})
+			// Files that are already uploaded are skipped so we account for
+			// the bytes that are not written.
+			_ = bar.Add64(files[i].Size - w.n)
+			if err != nil {
+				return fmt.Errorf("failed to upload %s: %w", files[i].Path, err)
+			}
+			uploaded[i] = ass
+			if versioned {
+				// The API returns the MD5 only if the content was there
+				// before.
+				sum, err := assets.FileMD5(files[i].Path)
+				if err != nil {
+					return err
+				}
+				return assets.RecordVersion(cmd.Context(), lim, files[i].Name, assets.Version{
+					Version:   versions[i],
+					Asset:     ass.Name,
+					AssetID:   ass.ID,
+					Md5:       sum,
+					Tags:      uploadTags,
+					Labels:    labels,
+					Metadata:  metadata,
+					CreatedAt: time.Now().UTC(),
+				})
+			}
+			return nil
+		})
 		if err != nil {
 			if errors.IsUnauthenticated(err) {
 				if err := config.Login(cmd.Context()); err != nil {
 					return err
 				}
-				fmt.Println("You are logged in now")
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 				return nil
 			}
 			return err
@@ -78,8 +204,131 @@ var PushCmd = &cobra.Command{
 		if err := bar.Close(); err != nil {
 			return err
 		}
-		fmt.Printf("ID: %s\n", ass.ID)
-		fmt.Printf("\nDone!\n")
-		return nil
+		if len(uploaded) == 1 {
+			fmt.Fprintf(cmd.OutOrStdout(), "ID: %s\n", uploaded[0].ID)
+			if versioned {
+				fmt.Fprintf(cmd.OutOrStdout(), "Version: %s\n", versions[0])
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "\nDone!\n")
+			return nil
+		}
+		fmt.Fprintln(cmd.OutOrStdout())
+		table := tablewriter.NewWriter(cmd.OutOrStdout())
+		table.Header([]string{"ID", "Name", "MD5"})
+		data := make([][]string, len(uploaded))
+		for i, ass := range uploaded {
+			data[i] = []string{ass.ID, ass.Name, ass.Md5}
+		}
+		if err := table.Bulk(data); err != nil {
+			return err
+		}
+		return table.Render()
 	},
 }
+
+// collectFilesToUpload expands the given paths into the list of files to
+// upload. Directories are walked recursively and filtered with the include
+// and exclude globs.
+func collectFilesToUpload(paths []string) ([]fileToUpload, error) {
+	for _, pattern := range append(append([]string{}, uploadInclude...), uploadExclude...) {
+		if !doublestar.ValidatePattern(pattern) {
+			return nil, fmt.Errorf("invalid glob pattern: %s", pattern)
+		}
+	}
+	var files []fileToUpload
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, fileToUpload{
+				Path: p,
+				Name: uploadPrefix + filepath.Base(p),
+				Size: info.Size(),
+			})
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			rel, err := filepath.Rel(p, path)
+			if err != nil {
+				return err
+			}
+			rel = filepath.ToSlash(rel)
+			if !matchesGlobs(rel, uploadInclude, uploadExclude) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			files = append(files, fileToUpload{
+				Path: path,
+				Name: uploadPrefix + rel,
+				Size: info.Size(),
+			})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk directory %s: %w", p, err)
+		}
+	}
+	return files, nil
+}
+
+// spoolStdin copies everything from stdin into a temporary file since the
+// size and MD5 of the content need to be known before uploading.
+func spoolStdin(stdin io.Reader) (fileToUpload, error) {
+	f, err := os.CreateTemp("", "lim-stdin-*")
+	if err != nil {
+		return fileToUpload{}, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer f.Close()
+	n, err := io.Copy(f, stdin)
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return fileToUpload{}, fmt.Errorf("failed to read stdin: %w", err)
+	}
+	return fileToUpload{
+		Path: f.Name(),
+		Name: uploadAssetName,
+		Size: n,
+	}, nil
+}
+
+// matchesGlobs returns whether the slash separated path matches any of the
+// include patterns, or there are none, and none of the exclude patterns.
+func matchesGlobs(p string, include, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := doublestar.Match(pattern, p); ok {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, pattern := range include {
+		if ok, _ := doublestar.Match(pattern, p); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
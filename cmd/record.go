@@ -0,0 +1,129 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/adb"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+)
+
+var (
+	recordOutput    string
+	recordDuration  time.Duration
+	recordPush      bool
+	recordAssetName string
+	recordADBPath   string
+)
+
+func init() {
+	RecordCmd.PersistentFlags().StringVarP(&recordOutput, "output", "o", "", "Output file. Defaults to <ID>-<timestamp>.mp4 in the current directory.")
+	RecordCmd.PersistentFlags().DurationVar(&recordDuration, "duration", 30*time.Second, "How long to record, at most 3m. Press Ctrl+C to stop earlier.")
+	RecordCmd.PersistentFlags().BoolVar(&recordPush, "push", false, "Upload the recording to the asset storage.")
+	RecordCmd.PersistentFlags().StringVarP(&recordAssetName, "name", "n", "", "Name of the asset when pushed. Defaults to file name.")
+	RecordCmd.PersistentFlags().StringVar(&recordADBPath, "adb-path", "adb", "Optional path to the adb binary, defaults to `adb`")
+	RootCmd.AddCommand(RecordCmd)
+}
+
+// RecordCmd represents the record command
+var RecordCmd = &cobra.Command{
+	Use:   "record [ID]",
+	Short: "Records the screen of the instance as MP4 video.",
+	Long: `Recording is supported for Android instances only.
+
+Examples:
+
+$ lim record <ID>
+$ lim record <ID> --duration 10s -o out.mp4 --push
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.AndroidInstances),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		out := recordOutput
+		if out == "" {
+			out = fmt.Sprintf("%s-%s.mp4", id, time.Now().Format("20060102-150405"))
+		}
+		switch strings.Split(id, "_")[0] {
+		case "android":
+		case "ios":
+			return errors.Newf(errors.KindUnsupported, "screen recording is not supported for iOS instances")
+		default:
+			return fmt.Errorf("invalid id: %s", id)
+		}
+		if recordDuration <= 0 || recordDuration > adb.MaxRecordDuration {
+			return fmt.Errorf("duration must be between 1s and %s", adb.MaxRecordDuration)
+		}
+		i, err := lim.AndroidInstances.Get(cmd.Context(), id)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to get Android instance %s: %w", id, err)
+		}
+		d, err := adb.Connect(i, recordADBPath)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		stop := make(chan struct{})
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(sigChan)
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-sigChan:
+				close(stop)
+			case <-done:
+			}
+		}()
+		fmt.Fprintf(cmd.OutOrStdout(), "Recording for %s. Press Ctrl+C to stop earlier.\n", recordDuration)
+		if err := d.Record(cmd.Context(), recordDuration, out, stop); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved recording to %s\n", out)
+		if recordPush {
+			ass, err := assets.Upload(cmd.Context(), lim, out, recordAssetName)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "ID: %s\n", ass.ID)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+// failFirst makes the next n requests fail with the given status and
+// Retry-After header.
+func (h *harness) failFirst(n int, status int, retryAfter string) {
+	next := h.handler
+	var failed atomic.Int32
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(failed.Add(1)) > n {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		http.Error(w, `{"message":"try again later"}`, status)
+	})
+}
+
+func TestRetries(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	h.failFirst(2, http.StatusTooManyRequests, "0")
+	if err := h.runWithFlags("get", "asset", "-v", "--retry-backoff", "1h"); err != nil {
+		t.Errorf("Retry-After was not honored: %s", err)
+	}
+	h.handler = h.server.Handler()
+	h.failFirst(3, http.StatusTooManyRequests, "0")
+	if err := h.runWithFlags("get", "asset", "--max-retries", "1"); errors.KindOf(err) != errors.KindQuotaExceeded {
+		t.Errorf("got %v, want a quota exceeded error", err)
+	}
+	h.handler = h.server.Handler()
+	h.failFirst(1, http.StatusBadGateway, "")
+	if err := h.runWithFlags("get", "asset", "--max-retries", "0"); errors.KindOf(err) != errors.KindNetwork {
+		t.Errorf("got %v, want a network error", err)
+	}
+	h.assertGolden("retries")
+}
+
+func TestRetriesCreatingInstances(t *testing.T) {
+	h := newHarness(t)
+	// The instance may have been created despite the error.
+	h.failFirst(1, http.StatusBadGateway, "")
+	if err := h.runWithFlags("run", "android", "--connect=false"); errors.KindOf(err) != errors.KindNetwork {
+		t.Errorf("got %v, want a network error", err)
+	}
+	// The API asks for it.
+	h.handler = h.server.Handler()
+	h.failFirst(1, http.StatusServiceUnavailable, "0")
+	if err := h.runWithFlags("run", "android", "--connect=false", "-v"); err != nil {
+		t.Errorf("Retry-After was not honored: %s", err)
+	}
+	h.runWithFlags("get", "android")
+	h.assertGolden("retries_creating_instances")
+}
+
+func TestTimeout(t *testing.T) {
+	h := newHarness(t)
+	next := h.handler
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		next.ServeHTTP(w, r)
+	})
+	if err := h.runWithFlags("get", "asset", "--timeout", "50ms"); errors.KindOf(err) != errors.KindTimeout {
+		t.Errorf("got %v, want a timeout error", err)
+	}
+	h.assertGolden("timeout")
+}
@@ -18,26 +18,68 @@ package cmd
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"golang.org/x/term"
 
 	limrun "github.com/limrun-inc/go-sdk"
 	"github.com/limrun-inc/go-sdk/option"
 	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+	"github.com/limrun-inc/lim/logging"
+	"github.com/limrun-inc/lim/release"
+	"github.com/limrun-inc/lim/retry"
+	"github.com/limrun-inc/lim/version"
 )
 
 var (
-	apiKeyFlagValue string
+	apiKeyFlagValue       string
+	apiEndpointFlagValue  string
+	errorFormatFlagValue  string
+	verbosityFlagValue    int
+	debugHTTPFlagValue    bool
+	logFormatFlagValue    string
+	timeoutFlagValue      time.Duration
+	maxRetriesFlagValue   int
+	retryBackoffFlagValue time.Duration
+	httpsProxyFlagValue   string
+	noProxyFlagValue      string
+	caFileFlagValue       string
+	clientCertFlagValue   string
+	clientKeyFlagValue    string
 )
 
 func init() {
 	RootCmd.PersistentFlags().StringVar(&apiKeyFlagValue, config.ConfigKeyAPIKey, "", "API Key to use to access Limrun")
+	RootCmd.PersistentFlags().StringVar(&apiEndpointFlagValue, config.ConfigKeyAPIEndpoint, "", "Base URL of the Limrun API, e.g. the address of lim dev server")
+	RootCmd.PersistentFlags().StringVar(&errorFormatFlagValue, config.ConfigKeyErrorFormat, "text", "Format of the error printed when a command fails, one of text or json. See lim help exit-codes.")
+	RootCmd.PersistentFlags().CountVarP(&verbosityFlagValue, config.ConfigKeyVerbosity, "v", "Log more details to stderr, -v for info and -vv for debug messages.")
+	RootCmd.PersistentFlags().BoolVar(&debugHTTPFlagValue, config.ConfigKeyDebugHTTP, false, "Log every API request with its redacted request and response bodies.")
+	RootCmd.PersistentFlags().StringVar(&logFormatFlagValue, config.ConfigKeyLogFormat, logging.FormatText, "Format of the logs, one of text or json.")
+	RootCmd.PersistentFlags().DurationVar(&timeoutFlagValue, config.ConfigKeyTimeout, 0, "Deadline of the command, e.g. 30s or 5m. Commands that keep a session open, e.g. run or connect, apply it to their API calls only. 0 means no deadline.")
+	RootCmd.PersistentFlags().IntVar(&maxRetriesFlagValue, config.ConfigKeyMaxRetries, 2, "Number of times an API call is retried after a connection error, a rate limit or a server error. Calls that create instances are retried only when the API asks for it.")
+	RootCmd.PersistentFlags().DurationVar(&retryBackoffFlagValue, config.ConfigKeyRetryBackoff, 500*time.Millisecond, "Delay before the first retry of an API call, doubled for every further retry. Retry-After of the API takes precedence.")
+	RootCmd.PersistentFlags().StringVar(&httpsProxyFlagValue, config.ConfigKeyHTTPSProxy, "", "Proxy for HTTPS and WebSocket connections, e.g. http://proxy.example.com:3128. Defaults to the HTTPS_PROXY environment variable.")
+	RootCmd.PersistentFlags().StringVar(&noProxyFlagValue, config.ConfigKeyNoProxy, "", "Comma separated hosts, domains and CIDR ranges to connect to without the proxy. Defaults to the NO_PROXY environment variable.")
+	RootCmd.PersistentFlags().StringVar(&caFileFlagValue, config.ConfigKeyCAFile, "", "PEM file of CA certificates to trust in addition to the ones of the system, e.g. of an intercepting proxy.")
+	RootCmd.PersistentFlags().StringVar(&clientCertFlagValue, config.ConfigKeyClientCert, "", "PEM file of the client certificate to present for mutual TLS.")
+	RootCmd.PersistentFlags().StringVar(&clientKeyFlagValue, config.ConfigKeyClientKey, "", "PEM file of the private key of the client certificate.")
+	RootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		if errorFormat() == "json" {
+			cmd.SilenceUsage = true
+		}
+		return errors.WithKind(errors.KindInvalidArgument, err)
+	})
 }
 
 var (
@@ -50,16 +92,54 @@ var RootCmd = &cobra.Command{
 	Use:   "lim",
 	Short: "Create and control sandboxes for your AI agents - Android, iOS, Chrome and more!",
 	Long:  `lim allows you to interact with Limrun to get sandbox environments for your AI agent to operate.`,
+	// Errors are printed by Execute in the format given with --error-format.
+	SilenceErrors: true,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The completion request command doesn't parse the flags of the
+		// command being completed, so completions set up the client
+		// themselves once they are parsed.
+		if cmd.Name() == cobra.ShellCompRequestCmd {
+			return nil
+		}
 		if err := initializeConfig(cmd); err != nil {
 			return err
 		}
+		if errorFormat() == "json" {
+			cmd.SilenceUsage = true
+		}
+		debugHTTP := viper.GetBool(config.ConfigKeyDebugHTTP)
+		if err := logging.Setup(cmd.ErrOrStderr(), viper.GetString(config.ConfigKeyLogFormat), viper.GetInt(config.ConfigKeyVerbosity), debugHTTP); err != nil {
+			return errors.WithKind(errors.KindInvalidArgument, err)
+		}
+		if err := config.ConfigureNetwork(); err != nil {
+			return errors.WithKind(errors.KindInvalidArgument, err)
+		}
+		if checkForUpdates(cmd) {
+			release.Check()
+		}
+		if _, ok := cmd.Annotations[config.AnnotationSession]; !ok {
+			ctx, cancel := config.WithTimeout(cmd.Context())
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+		// A client that is already in the context, e.g. one injected by
+		// tests, takes precedence.
+		if _, err := config.ClientFromContext(cmd.Context()); err == nil {
+			return nil
+		}
 		opts := []option.RequestOption{
 			option.WithAPIKey(viper.GetString(config.ConfigKeyAPIKey)),
 			option.WithBaseURL(viper.GetString(config.ConfigKeyAPIEndpoint)),
+			// Retries are done by the middleware so that the backoff can
+			// be configured and waiting stops at the deadline.
+			option.WithMaxRetries(0),
+			option.WithMiddleware(
+				retry.Middleware(viper.GetInt(config.ConfigKeyMaxRetries), viper.GetDuration(config.ConfigKeyRetryBackoff)),
+				logging.Middleware(debugHTTP),
+			),
 		}
 		lim := limrun.NewClient(opts...)
-		cmd.SetContext(context.WithValue(cmd.Context(), "lim", lim))
+		cmd.SetContext(config.WithClient(cmd.Context(), lim))
 		return nil
 	},
 }
@@ -78,6 +158,8 @@ func initializeConfig(cmd *cobra.Command) error {
 	viper.AutomaticEnv()
 	viper.SetDefault(config.ConfigKeyAPIEndpoint, "https://api.limrun.com")
 	viper.SetDefault(config.ConfigKeyConsoleEndpoint, "https://console.limrun.com")
+	viper.SetDefault(config.ConfigKeyCacheDir, filepath.Join(defaultConfigDir, "cache"))
+	viper.SetDefault(config.ConfigKeyUpdateCheck, true)
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 	viper.AddConfigPath("/etc/lim/")
@@ -85,23 +167,147 @@ func initializeConfig(cmd *cobra.Command) error {
 	viper.AddConfigPath(".")
 	if err := viper.ReadInConfig(); err != nil {
 		// It's okay if the config file doesn't exist.
-		if !errors.As(err, &configFileNotFoundError) {
+		if !goerrors.As(err, &configFileNotFoundError) {
 			return err
 		}
 	}
-	if err := viper.SafeWriteConfigAs(filepath.Join(defaultConfigDir, "config.yaml")); err != nil && !errors.As(err, &configFileAlreadyExistsError) {
+	defaultConfigFile := filepath.Join(defaultConfigDir, "config.yaml")
+	if err := viper.SafeWriteConfigAs(defaultConfigFile); err != nil && !goerrors.As(err, &configFileAlreadyExistsError) {
 		return fmt.Errorf("failed to write initial config: %v", err)
 	}
+	// The initial config is the one in use from now on, e.g. for plugins to
+	// be told about.
+	if viper.ConfigFileUsed() == "" {
+		viper.SetConfigFile(defaultConfigFile)
+	}
 	return viper.BindPFlags(cmd.Flags())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The process exits with the code of the kind of the error if the command
+// fails.
 func Execute() {
-	err := RootCmd.Execute()
+	if err := execute(context.Background(), os.Args[1:]); err != nil {
+		printError(RootCmd.ErrOrStderr(), err)
+		os.Exit(errors.ExitCode(err))
+	}
+}
+
+var (
+	classifyArgsOnce sync.Once
+
+	// cancelTimeout releases the deadline of the command set up with
+	// --timeout.
+	cancelTimeout context.CancelFunc = func() {}
+)
+
+// execute runs the root command with the arguments and marks the errors of
+// the command line, e.g. a missing argument or an unknown command, as invalid
+// arguments. Aliases are expanded first and arguments that don't start with
+// a built-in command may run a plugin instead.
+func execute(ctx context.Context, args []string) error {
+	classifyArgsOnce.Do(func() { classifyArgs(RootCmd) })
+	args, err := expandAliases(args)
 	if err != nil {
-		os.Exit(1)
+		return err
+	}
+	if path, pluginArgs, ok := findPlugin(args); ok {
+		return runPlugin(ctx, path, pluginArgs)
+	}
+	RootCmd.SetArgs(args)
+	cmd, err := RootCmd.ExecuteContextC(ctx)
+	cancelTimeout()
+	if d := viper.GetDuration(config.ConfigKeyTimeout); d > 0 && goerrors.Is(err, context.DeadlineExceeded) {
+		err = errors.WithKind(errors.KindTimeout, fmt.Errorf("timed out after %s, see --timeout: %w", d, err))
+	}
+	// The root command doesn't run by itself, so it fails only when the
+	// command line doesn't name a command.
+	if err != nil && cmd == RootCmd && errors.KindOf(err) == errors.KindUnknown {
+		err = errors.WithKind(errors.KindInvalidArgument, err)
+	}
+	if cmd != nil && checkForUpdates(cmd) {
+		if notice := release.Notice(); notice != "" {
+			fmt.Fprintf(cmd.ErrOrStderr(), "\n%s\n", notice)
+		}
+	}
+	return err
+}
+
+// checkForUpdates returns whether to look for a newer release of lim. It's
+// skipped for development builds, outside of terminals, e.g. in scripts and
+// CI, and for the commands that deal with the version themselves.
+func checkForUpdates(cmd *cobra.Command) bool {
+	if !viper.GetBool(config.ConfigKeyUpdateCheck) || version.Version == "v0.0.0" || os.Getenv("CI") != "" {
+		return false
+	}
+	if cmd == VersionCmd || cmd == UpgradeCmd || cmd.Name() == cobra.ShellCompRequestCmd {
+		return false
+	}
+	return cmd.ErrOrStderr() == os.Stderr && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// classifyArgs wraps the argument validators of the command and its
+// subcommands so that their errors are invalid arguments.
+func classifyArgs(c *cobra.Command) {
+	if args := c.Args; args != nil {
+		c.Args = func(cmd *cobra.Command, a []string) error {
+			return errors.WithKind(errors.KindInvalidArgument, args(cmd, a))
+		}
+	}
+	for _, sub := range c.Commands() {
+		classifyArgs(sub)
+	}
+}
+
+// errorFormat returns the format errors are printed in. The command line and
+// the environment are looked at when the config isn't initialized, e.g. if
+// the command is unknown or parsing the flags failed.
+func errorFormat() string {
+	if f := viper.GetString(config.ConfigKeyErrorFormat); f != "" {
+		return f
+	}
+	for i, arg := range os.Args {
+		if f, ok := strings.CutPrefix(arg, "--"+config.ConfigKeyErrorFormat+"="); ok {
+			return f
+		}
+		if arg == "--"+config.ConfigKeyErrorFormat && i+1 < len(os.Args) {
+			return os.Args[i+1]
+		}
+	}
+	if f := os.Getenv("LIM_ERROR_FORMAT"); f != "" {
+		return f
+	}
+	return errorFormatFlagValue
+}
+
+// printError prints the error in the format given with --error-format.
+// Exit codes of plugins are not printed.
+func printError(w io.Writer, err error) {
+	var exitErr *errors.ExitError
+	if goerrors.As(err, &exitErr) {
+		return
+	}
+	if errorFormat() != "json" {
+		fmt.Fprintf(w, "Error: %s\n", err)
+		return
 	}
+	kind := errors.KindOf(err)
+	out := struct {
+		Error struct {
+			Kind       errors.Kind `json:"kind"`
+			Message    string      `json:"message"`
+			ExitCode   int         `json:"exitCode"`
+			StatusCode int         `json:"statusCode,omitempty"`
+			RequestID  string      `json:"requestId,omitempty"`
+		} `json:"error"`
+	}{}
+	out.Error.Kind = kind
+	out.Error.Message = err.Error()
+	out.Error.ExitCode = kind.ExitCode()
+	out.Error.StatusCode = errors.StatusCode(err)
+	out.Error.RequestID = errors.RequestID(err)
+	_ = json.NewEncoder(w).Encode(out)
 }
 
 func init() {
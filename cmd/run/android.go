@@ -17,11 +17,16 @@ limitations under the License.
 package run
 
 import (
+	"context"
 	"fmt"
 	"github.com/limrun-inc/go-sdk/packages/param"
+	"github.com/limrun-inc/lim/adb"
+	"github.com/limrun-inc/lim/artifact"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
 	"github.com/limrun-inc/lim/config"
 	"github.com/limrun-inc/lim/errors"
-	"github.com/schollz/progressbar/v3"
+	limviewer "github.com/limrun-inc/lim/viewer"
 	"os"
 	"os/exec"
 	"os/signal"
@@ -31,86 +36,151 @@ import (
 	"time"
 
 	limrun "github.com/limrun-inc/go-sdk"
-	"github.com/limrun-inc/go-sdk/tunnel"
 	"github.com/spf13/cobra"
 )
 
+const (
+	viewerNone    = "none"
+	viewerScrcpy  = "scrcpy"
+	viewerBrowser = "browser"
+)
+
 var (
-	adbPath      string
-	connect      bool
-	stream       bool
-	deleteOnExit bool
+	adbPath        string
+	scrcpyPath     string
+	bundletoolPath string
+	scrcpyArgs     []string
+	viewer         string
+	connect        bool
+	stream         bool
+	deleteOnExit   bool
+	region         string
 
 	assetNamesToInstall []string
 	localAppsToInstall  []string
+	versioned           bool
 )
 
 func init() {
 	AndroidCmd.PersistentFlags().StringVar(&adbPath, "adb-path", "adb", "Optional path to the adb binary, defaults to `adb`")
 	AndroidCmd.PersistentFlags().BoolVar(&connect, "connect", true, "Connect to the Android instance, e.g. start ADB tunnel. Default is true.")
+	AndroidCmd.PersistentFlags().StringVar(&scrcpyPath, "scrcpy-path", "scrcpy", "Optional path to the scrcpy binary, defaults to `scrcpy`")
+	AndroidCmd.PersistentFlags().StringArrayVar(&scrcpyArgs, "scrcpy-arg", []string{}, "Additional argument to pass to scrcpy, e.g. --scrcpy-arg=--max-size=1024. Can be repeated.")
+	AndroidCmd.PersistentFlags().StringVar(&viewer, "viewer", viewerScrcpy, "How to view and control the Android instance, one of none, scrcpy or browser. Connect flag must be true.")
 	AndroidCmd.PersistentFlags().BoolVar(&stream, "stream", true, "Stream the Android instance for control. Default is true. Connect flag must be true.")
+	_ = AndroidCmd.PersistentFlags().MarkDeprecated("stream", "use --viewer=none instead")
 	AndroidCmd.PersistentFlags().BoolVar(&deleteOnExit, "rm", false, "Delete the instance on exit. Default is false.")
-	AndroidCmd.PersistentFlags().StringArrayVar(&assetNamesToInstall, "install-asset", []string{}, "List of asset names to install. It will return error if they are not already uploaded. Asset names that will be installed together should be separated by comma.")
-	AndroidCmd.PersistentFlags().StringArrayVar(&localAppsToInstall, "install", []string{}, "List of local app files to install. If not uploaded already, they will be uploaded to the asset storage first. Files that will be installed together should be separated by comma.")
+	AndroidCmd.PersistentFlags().StringArrayVar(&assetNamesToInstall, "install-asset", []string{}, "List of asset names to install, name@version and name:tag are accepted too. Names must match exactly. It will return error if they are not already uploaded. Asset names that will be installed together should be separated by comma.")
+	AndroidCmd.PersistentFlags().StringArrayVar(&localAppsToInstall, "install", []string{}, "List of local app files to install. If not uploaded already, they will be uploaded to the asset storage first. Files that will be installed together should be separated by comma. App bundles (.aab) are converted into split APKs and XAPK archives are extracted.")
+	AndroidCmd.PersistentFlags().StringVar(&bundletoolPath, "bundletool-path", "bundletool", "Optional path to the bundletool binary or jar file used to convert app bundles, defaults to `bundletool`")
+	AndroidCmd.PersistentFlags().StringVar(&region, "region", "", "Region to create the instance in. Decided by the API if not given.")
+	AndroidCmd.PersistentFlags().BoolVar(&versioned, "versioned", false, "Upload local apps as new versions of their file name, e.g. app.apk@3, with their package and version attached. Assets have no metadata of their own, so without it the package and version are only printed.")
+	_ = AndroidCmd.RegisterFlagCompletionFunc("install-asset", completion.Assets)
+	_ = AndroidCmd.RegisterFlagCompletionFunc("region", completion.Regions)
 }
 
 // AndroidCmd represents the connect command for Android
 var AndroidCmd = &cobra.Command{
-	Use:   "android",
-	Short: "Creates a new Android instance, connects and starts streaming.",
+	Use:         "android",
+	Short:       "Creates a new Android instance, connects and starts streaming.",
+	Annotations: map[string]string{config.AnnotationSession: ""},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		lim := cmd.Context().Value("lim").(limrun.Client)
+		if !stream {
+			viewer = viewerNone
+		}
+		var required []requiredBinary
+		if connect {
+			required = append(required, requiredBinary{Name: "adb", Path: adbPath, Flag: "adb-path"})
+			switch viewer {
+			case viewerNone:
+			case viewerScrcpy:
+				required = append(required, requiredBinary{Name: "scrcpy", Path: scrcpyPath, Flag: "scrcpy-path"})
+			case viewerBrowser:
+			default:
+				return errors.Newf(errors.KindInvalidArgument, "invalid viewer %q, must be one of none, scrcpy or browser", viewer)
+			}
+		}
+		if hasAppBundle(localAppsToInstall) {
+			if strings.HasSuffix(bundletoolPath, ".jar") {
+				required = append(required, requiredBinary{Name: "java", Path: "java", Flag: "bundletool-path"})
+			} else {
+				required = append(required, requiredBinary{Name: "bundletool", Path: bundletoolPath, Flag: "bundletool-path"})
+			}
+		}
+		if err := preflight(required...); err != nil {
+			return err
+		}
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
 		var finalAssetNamesToInstall [][]string
 		if len(assetNamesToInstall) > 0 {
+			resolver := assets.InteractiveResolver(false)
 			for _, assetName := range assetNamesToInstall {
 				var arr []string
 				for _, n := range strings.Split(assetName, ",") {
 					if n == "" {
 						continue
 					}
-					arr = append(arr, n)
+					ass, err := resolver.Resolve(cmd.Context(), lim, n)
+					if err != nil {
+						if errors.IsUnauthenticated(err) {
+							if err := config.Login(cmd.Context()); err != nil {
+								return err
+							}
+							fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+							return nil
+						}
+						return err
+					}
+					arr = append(arr, ass.Name)
 				}
 				finalAssetNamesToInstall = append(finalAssetNamesToInstall, arr)
 			}
 		}
 		if len(localAppsToInstall) > 0 {
+			tmpDir, err := os.MkdirTemp("", "lim-artifacts-")
+			if err != nil {
+				return fmt.Errorf("failed to create temporary directory: %w", err)
+			}
+			defer os.RemoveAll(tmpDir)
 			for _, appPaths := range localAppsToInstall {
 				var assetNamesForSingleApp []string
 				for _, singleApkPath := range strings.Split(appPaths, ",") {
 					if singleApkPath == "" {
 						continue
 					}
-					f, err := os.Stat(singleApkPath)
+					a, err := artifact.Prepare(cmd.Context(), singleApkPath, artifact.Options{
+						Dir:            tmpDir,
+						BundletoolPath: bundletoolPath,
+					})
 					if err != nil {
 						return err
 					}
-					fmt.Printf("%s\n", filepath.Base(singleApkPath))
-					bar := progressbar.DefaultBytes(
-						f.Size(),
-						"",
-					)
-					ass, err := lim.Assets.GetOrUpload(cmd.Context(), limrun.AssetGetOrUploadParams{
-						Name:           param.NewOpt(filepath.Base(singleApkPath)),
-						Path:           singleApkPath,
-						ProgressWriter: bar,
-					})
-					if err != nil {
-						return fmt.Errorf("failed to upload app at %s: %w", singleApkPath, err)
+					if a.Info.Package != "" {
+						fmt.Fprintf(cmd.OutOrStdout(), "%s: %s %s (%s)\n", filepath.Base(singleApkPath), a.Info.Package, a.Info.Version, a.Info.Build)
 					}
-					if err := bar.Close(); err != nil {
+					uploaded, err := assets.UploadArtifact(cmd.Context(), lim, a, versioned)
+					if err != nil {
 						return err
 					}
-					assetNamesForSingleApp = append(assetNamesForSingleApp, ass.Name)
+					for _, ass := range uploaded {
+						assetNamesForSingleApp = append(assetNamesForSingleApp, ass.Name)
+					}
 				}
 				finalAssetNamesToInstall = append(finalAssetNamesToInstall, assetNamesForSingleApp)
 			}
-			fmt.Printf("Successfully uploaded %d file(s)\n", len(localAppsToInstall))
+			fmt.Fprintf(cmd.OutOrStdout(), "Successfully uploaded %d file(s)\n", len(localAppsToInstall))
 		}
 		st := time.Now()
 		params := limrun.AndroidInstanceNewParams{
 			Wait: param.NewOpt(true),
 			Spec: limrun.AndroidInstanceNewParamsSpec{},
 		}
+		if region != "" {
+			params.Spec.Region = param.NewOpt(region)
+		}
 		if len(finalAssetNamesToInstall) > 0 {
 			for _, assetNames := range finalAssetNamesToInstall {
 				params.Spec.InitialAssets = append(params.Spec.InitialAssets, limrun.AndroidInstanceNewParamsSpecInitialAsset{
@@ -120,13 +190,17 @@ var AndroidCmd = &cobra.Command{
 				})
 			}
 		}
-		i, err := lim.AndroidInstances.New(cmd.Context(), params)
+		ctx, cancel := config.WithTimeout(cmd.Context())
+		defer cancel()
+		stopWaiting := stillWaiting(cmd.ErrOrStderr(), "Waiting for the instance to be ready")
+		i, err := lim.AndroidInstances.New(ctx, params)
+		stopWaiting()
 		if err != nil {
 			if errors.IsUnauthenticated(err) {
 				if err := config.Login(cmd.Context()); err != nil {
 					return err
 				}
-				fmt.Println("You are logged in now")
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
 				return nil
 			}
 			return fmt.Errorf("failed to create a new Android instance: %w", err)
@@ -134,36 +208,52 @@ var AndroidCmd = &cobra.Command{
 		if deleteOnExit {
 			defer func() {
 				if err := lim.AndroidInstances.Delete(cmd.Context(), i.Metadata.ID); err != nil {
-					fmt.Printf("Failed to delete instance: %s", err)
+					fmt.Fprintf(cmd.OutOrStdout(), "Failed to delete instance: %s", err)
 					return
 				}
-				fmt.Printf("%s is deleted\n", i.Metadata.ID)
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is deleted\n", i.Metadata.ID)
 			}()
 		}
-		fmt.Printf("Created a new instance in %s\n", time.Since(st))
+		fmt.Fprintf(cmd.OutOrStdout(), "Created a new instance in %s\n", time.Since(st))
 		if connect {
 			sigChan := make(chan os.Signal, 1)
 			signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-			t, err := tunnel.New(i.Status.AdbWebSocketURL, i.Status.Token, tunnel.WithADBPath(adbPath))
+			d, err := adb.Connect(i, adbPath)
 			if err != nil {
-				return fmt.Errorf("failed to create tunnel: %w", err)
-			}
-			if err := t.Start(); err != nil {
-				return fmt.Errorf("failed to start tunnel: %w", err)
+				return err
 			}
-			defer t.Close()
-			if stream {
+			defer d.Close()
+			if viewer == viewerScrcpy {
 				go func() {
-					if out, err := exec.CommandContext(cmd.Context(), "scrcpy", "-s", t.Addr()).CombinedOutput(); err != nil {
-						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "failed to start scrcpy: %s %s", err.Error(), string(out))
+					c := exec.CommandContext(cmd.Context(), scrcpyPath, append([]string{"-s", d.Serial}, scrcpyArgs...)...)
+					c.Stdout = cmd.OutOrStdout()
+					c.Stderr = cmd.ErrOrStderr()
+					if err := c.Run(); err != nil {
+						_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "scrcpy exited: %s\n", err)
 					}
 					sigChan <- syscall.SIGTERM
 				}()
 			}
-			fmt.Println("Tunnel started. Press Ctrl+C to stop.")
+			if viewer == viewerBrowser {
+				v, err := limviewer.New(i.Status.EndpointWebSocketURL, i.Status.Token)
+				if err != nil {
+					return err
+				}
+				v.Start()
+				defer func() {
+					ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+					defer cancel()
+					_ = v.Close(ctx)
+				}()
+				fmt.Fprintf(cmd.OutOrStdout(), "Viewer is available at %s\n", v.URL())
+				if err := config.OpenBrowser(v.URL()); err != nil {
+					return err
+				}
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Tunnel started. Press Ctrl+C to stop.")
 			select {
 			case sig := <-sigChan:
-				fmt.Printf("Received signal %v, stopping tunnel...\n", sig)
+				fmt.Fprintf(cmd.OutOrStdout(), "Received signal %v, stopping tunnel...\n", sig)
 			}
 		} else {
 			cmd.Printf("Created instance %s\n", i.Metadata.ID)
@@ -171,3 +261,16 @@ var AndroidCmd = &cobra.Command{
 		return nil
 	},
 }
+
+// hasAppBundle returns whether any of the comma separated groups of files
+// contains an app bundle that needs bundletool to be converted.
+func hasAppBundle(groups []string) bool {
+	for _, group := range groups {
+		for _, p := range strings.Split(group, ",") {
+			if kind, err := artifact.Detect(p); err == nil && kind == artifact.KindAAB {
+				return true
+			}
+		}
+	}
+	return false
+}
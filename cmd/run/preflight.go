@@ -0,0 +1,82 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package run
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// installHints maps binary names to install instructions per OS.
+var installHints = map[string]map[string]string{
+	"adb": {
+		"darwin":  "brew install android-platform-tools",
+		"linux":   "sudo apt install adb",
+		"windows": "download Android SDK Platform Tools from https://developer.android.com/tools/releases/platform-tools",
+	},
+	"scrcpy": {
+		"darwin":  "brew install scrcpy",
+		"linux":   "sudo apt install scrcpy",
+		"windows": "download scrcpy from https://github.com/Genymobile/scrcpy/releases",
+	},
+	"bundletool": {
+		"darwin":  "brew install bundletool",
+		"linux":   "download bundletool from https://github.com/google/bundletool/releases",
+		"windows": "download bundletool from https://github.com/google/bundletool/releases",
+	},
+	"java": {
+		"darwin":  "brew install openjdk",
+		"linux":   "sudo apt install default-jre",
+		"windows": "download Java from https://adoptium.net",
+	},
+}
+
+// requiredBinary is an executable that needs to be present before an
+// instance is created.
+type requiredBinary struct {
+	// Name is the well-known name of the binary, e.g. adb.
+	Name string
+
+	// Path is what the user configured, either a name in PATH or a path.
+	Path string
+
+	// Flag is the flag that can be used to override the path.
+	Flag string
+}
+
+// preflight makes sure all given binaries can be executed so that we fail
+// before creating any instance.
+func preflight(bins ...requiredBinary) error {
+	var missing []string
+	for _, b := range bins {
+		if _, err := exec.LookPath(b.Path); err == nil {
+			continue
+		}
+		msg := fmt.Sprintf("%s could not be found at %q", b.Name, b.Path)
+		if hint, ok := installHints[b.Name][runtime.GOOS]; ok {
+			msg += fmt.Sprintf(", install it with `%s`", hint)
+		}
+		msg += fmt.Sprintf(" or point --%s to it", b.Flag)
+		missing = append(missing, msg)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required binaries:\n  %s", strings.Join(missing, "\n  "))
+	}
+	return nil
+}
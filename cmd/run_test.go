@@ -0,0 +1,89 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestRunAndroid(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("app.apk", []byte("fake apk"))
+	h.run("run", "android", "--connect=false", "--install-asset", "app.apk")
+	h.run("run", "android", "--connect=false", "--install-asset", "missing.apk")
+	h.run("get", "android")
+	h.run("delete", "android", "android_00000000000000000000000002")
+	h.run("get", "android")
+	h.assertGolden("run_android")
+}
+
+func TestRunAndroidInstallsUnreadableAPK(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile("app.apk", "not a zip")
+	if err := h.run("run", "android", "--connect=false", "--install", "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	h.run("get", "asset")
+	h.assertGolden("run_android_unreadable_apk")
+}
+
+func TestRunAndroidReuploadsOverwrittenAsset(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile("app.apk", "not a zip")
+	if err := h.run("run", "android", "--connect=false", "--install", "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	// Someone else uploads different content with the same name.
+	h.server.PutAsset("app.apk", []byte("someone else's"))
+	if err := h.run("run", "android", "--connect=false", "--install", "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	h.run("get", "asset")
+	h.assertGolden("run_android_reuploads_overwritten_asset")
+}
+
+func TestRunAndroidSkipsUnchangedFile(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile("app.apk", "not a zip")
+	if err := h.run("run", "android", "--connect=false", "--install", "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	// The content changes but the size and modification time don't, so the
+	// file would only be uploaded again if it's hashed.
+	info, err := os.Stat("app.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.writeFile("app.apk", "not a ZIP")
+	if err := os.Chtimes("app.apk", info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	next := h.handler
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut && r.URL.Path == "/v1/assets" {
+			t.Errorf("looked up the asset by content although the file is unchanged")
+		}
+		next.ServeHTTP(w, r)
+	})
+	if err := h.run("run", "android", "--connect=false", "--install", "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	h.run("get", "asset")
+	h.assertGolden("run_android_skips_unchanged_file")
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/adb"
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+)
+
+var (
+	screenshotOutput    string
+	screenshotPush      bool
+	screenshotAssetName string
+	screenshotADBPath   string
+)
+
+func init() {
+	ScreenshotCmd.PersistentFlags().StringVarP(&screenshotOutput, "output", "o", "", "Output file. Defaults to <ID>-<timestamp>.png in the current directory.")
+	ScreenshotCmd.PersistentFlags().BoolVar(&screenshotPush, "push", false, "Upload the screenshot to the asset storage.")
+	ScreenshotCmd.PersistentFlags().StringVarP(&screenshotAssetName, "name", "n", "", "Name of the asset when pushed. Defaults to file name.")
+	ScreenshotCmd.PersistentFlags().StringVar(&screenshotADBPath, "adb-path", "adb", "Optional path to the adb binary, defaults to `adb`")
+	RootCmd.AddCommand(ScreenshotCmd)
+}
+
+// ScreenshotCmd represents the screenshot command
+var ScreenshotCmd = &cobra.Command{
+	Use:   "screenshot [ID]",
+	Short: "Takes a PNG screenshot of the instance.",
+	Long: `Screenshots are supported for Android instances only.
+
+Examples:
+
+$ lim screenshot <ID>
+$ lim screenshot <ID> -o out.png --push
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.AndroidInstances),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		out := screenshotOutput
+		if out == "" {
+			out = fmt.Sprintf("%s-%s.png", id, time.Now().Format("20060102-150405"))
+		}
+		var buf bytes.Buffer
+		switch strings.Split(id, "_")[0] {
+		case "android":
+			i, err := lim.AndroidInstances.Get(cmd.Context(), id)
+			if err != nil {
+				if errors.IsUnauthenticated(err) {
+					if err := config.Login(cmd.Context()); err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+					return nil
+				}
+				return fmt.Errorf("failed to get Android instance %s: %w", id, err)
+			}
+			d, err := adb.Connect(i, screenshotADBPath)
+			if err != nil {
+				return err
+			}
+			defer d.Close()
+			if err := d.Screenshot(cmd.Context(), &buf); err != nil {
+				return err
+			}
+		case "ios":
+			return errors.Newf(errors.KindUnsupported, "screenshots are not supported for iOS instances yet")
+		default:
+			return fmt.Errorf("invalid id: %s", id)
+		}
+		if err := os.WriteFile(out, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", out, err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Saved screenshot to %s\n", out)
+		if screenshotPush {
+			ass, err := assets.Upload(cmd.Context(), lim, out, screenshotAssetName)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "ID: %s\n", ass.ID)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,341 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	goerrors "errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/schollz/progressbar/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/limrun-inc/go-sdk/packages/param"
+
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+)
+
+const (
+	syncDirectionUp   = "up"
+	syncDirectionDown = "down"
+	syncDirectionBoth = "both"
+
+	syncActionUpload       = "upload"
+	syncActionDownload     = "download"
+	syncActionDeleteRemote = "delete remote"
+	syncActionDeleteLocal  = "delete local"
+	syncActionConflict     = "conflict"
+)
+
+var (
+	syncDirection   string
+	syncPrefix      string
+	syncDelete      bool
+	syncDryRun      bool
+	syncYes         bool
+	syncConcurrency int
+)
+
+func init() {
+	SyncCmd.PersistentFlags().StringVar(&syncDirection, "direction", syncDirectionUp, "Direction to sync in, one of up, down or both.")
+	SyncCmd.PersistentFlags().StringVar(&syncPrefix, "prefix", "", "Only sync assets whose name starts with the prefix. It's stripped to get the local path.")
+	SyncCmd.PersistentFlags().BoolVar(&syncDelete, "delete", false, "Delete files on the receiving side that don't exist on the sending side. Not allowed with --direction both.")
+	SyncCmd.PersistentFlags().BoolVar(&syncDryRun, "dry-run", false, "Only show the plan without transferring anything.")
+	SyncCmd.PersistentFlags().BoolVarP(&syncYes, "yes", "y", false, "Delete assets with --direction up --delete and no --prefix without asking for confirmation.")
+	SyncCmd.PersistentFlags().IntVar(&syncConcurrency, "concurrency", 4, "Number of files to transfer in parallel.")
+	RootCmd.AddCommand(SyncCmd)
+}
+
+// syncItem is a single planned change.
+type syncItem struct {
+	Action string
+	Name   string
+	Path   string
+	Remote limrun.Asset
+	Reason string
+}
+
+// SyncCmd represents the sync command
+var SyncCmd = &cobra.Command{
+	Use:   "sync [directory]",
+	Short: "Syncs a local directory with the asset storage.",
+	Long: `Local files and assets are compared by name and MD5 and only the differences
+are transferred. The relative path of each file is used as its asset name.
+Running it again when nothing changed does nothing. Versions of assets, e.g.
+app.apk@3, are left out since they are managed by lim push.
+
+With --direction both, a file whose content differs between the two sides is
+reported as a conflict, left untouched and makes the command fail.
+
+With --direction up --delete and no --prefix, every asset that doesn't exist
+in the directory is deleted, so the plan is printed first and confirmation is
+asked for, or --yes is required when stdin is not a terminal.
+
+Examples:
+
+$ lim sync ./builds
+$ lim sync ./media --direction down --prefix media/ --delete
+$ lim sync ./fixtures --direction both --dry-run
+`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := args[0]
+		switch syncDirection {
+		case syncDirectionUp, syncDirectionDown:
+		case syncDirectionBoth:
+			if syncDelete {
+				return fmt.Errorf("--delete cannot be used with --direction both")
+			}
+		default:
+			return fmt.Errorf("invalid direction %q, must be one of up, down or both", syncDirection)
+		}
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		params := limrun.AssetListParams{
+			IncludeDownloadURL: param.NewOpt(true),
+		}
+		if syncPrefix != "" {
+			params.NameFilter = param.NewOpt(syncPrefix)
+		}
+		fetched, err := lim.Assets.List(cmd.Context(), params)
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to list assets: %w", err)
+		}
+		remote := map[string]limrun.Asset{}
+		for _, ass := range *fetched {
+			// Versions and their indexes are managed by lim push.
+			if assets.IsIndex(ass.Name) || assets.IsVersion(ass.Name) {
+				continue
+			}
+			if rel, ok := strings.CutPrefix(ass.Name, syncPrefix); ok && rel != "" {
+				remote[rel] = ass
+			}
+		}
+		local, err := localFileHashes(dir)
+		if err != nil {
+			return err
+		}
+		plan := planSync(dir, local, remote)
+		if len(plan) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "Everything is in sync.")
+			return nil
+		}
+		table := tablewriter.NewWriter(cmd.OutOrStdout())
+		table.Header([]string{"Action", "Name", "Reason"})
+		data := make([][]string, len(plan))
+		for i, item := range plan {
+			data[i] = []string{item.Action, item.Name, item.Reason}
+		}
+		if err := table.Bulk(data); err != nil {
+			return err
+		}
+		if err := table.Render(); err != nil {
+			return err
+		}
+		if syncDryRun {
+			return nil
+		}
+		if ok, err := confirmSyncDelete(cmd, plan); err != nil || !ok {
+			return err
+		}
+		bar := progressbar.DefaultBytes(
+			-1,
+			fmt.Sprintf("%d change(s)", len(plan)),
+		)
+		err = parallel(syncConcurrency, len(plan), func(i int) error {
+			item := plan[i]
+			switch item.Action {
+			case syncActionUpload:
+				if _, err := lim.Assets.GetOrUpload(cmd.Context(), limrun.AssetGetOrUploadParams{
+					Path:           item.Path,
+					Name:           param.NewOpt(item.Name),
+					ProgressWriter: bar,
+				}); err != nil {
+					return fmt.Errorf("failed to upload %s: %w", item.Path, err)
+				}
+			case syncActionDownload:
+				if err := assets.Download(cmd.Context(), lim, item.Remote, item.Path, bar); err != nil && !goerrors.Is(err, assets.ErrUpToDate) {
+					return fmt.Errorf("failed to download %s: %w", item.Name, err)
+				}
+			case syncActionDeleteRemote:
+				if err := assets.Delete(cmd.Context(), lim, item.Remote.ID); err != nil {
+					return fmt.Errorf("failed to delete asset %s: %w", item.Name, err)
+				}
+			case syncActionDeleteLocal:
+				if err := os.Remove(item.Path); err != nil {
+					return fmt.Errorf("failed to delete %s: %w", item.Path, err)
+				}
+			}
+			return nil
+		})
+		_ = bar.Finish()
+		fmt.Fprintln(cmd.OutOrStdout())
+		if err != nil {
+			return err
+		}
+		var conflicts int
+		for _, item := range plan {
+			if item.Action == syncActionConflict {
+				conflicts++
+			}
+		}
+		if conflicts > 0 {
+			return fmt.Errorf("%d conflict(s) were left untouched", conflicts)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Done!\n")
+		return nil
+	},
+}
+
+// confirmSyncDelete asks for confirmation when the plan deletes remote assets
+// without a prefix limiting them, since that may be all assets of the
+// organization. It returns false if the user declines.
+func confirmSyncDelete(cmd *cobra.Command, plan []syncItem) (bool, error) {
+	if syncPrefix != "" || syncYes {
+		return true, nil
+	}
+	var deletes int
+	for _, item := range plan {
+		if item.Action == syncActionDeleteRemote {
+			deletes++
+		}
+	}
+	if deletes == 0 {
+		return true, nil
+	}
+	if cmd.InOrStdin() != os.Stdin || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, errors.Newf(errors.KindInvalidArgument, "--delete without --prefix would delete %d asset(s) that don't exist locally, limit it with --prefix or confirm with --yes", deletes)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Delete %d asset(s) that don't exist locally? [y/N] ", deletes)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		fmt.Fprintln(cmd.OutOrStdout(), "Aborted.")
+		return false, nil
+	}
+	return true, nil
+}
+
+// localFileHashes returns the MD5 of every regular file in dir keyed by its
+// slash separated relative path.
+func localFileHashes(dir string) (map[string]string, error) {
+	local := map[string]string{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		// Partial downloads are not part of the local state.
+		if !d.Type().IsRegular() || strings.HasSuffix(path, ".part") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := assets.FileMD5(path)
+		if err != nil {
+			return err
+		}
+		local[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk directory %s: %w", dir, err)
+	}
+	return local, nil
+}
+
+// planSync compares the local files with the remote assets, both keyed by
+// relative name, and returns the changes needed for the configured
+// direction sorted by name.
+func planSync(dir string, local map[string]string, remote map[string]limrun.Asset) []syncItem {
+	var plan []syncItem
+	up := syncDirection == syncDirectionUp || syncDirection == syncDirectionBoth
+	down := syncDirection == syncDirectionDown || syncDirection == syncDirectionBoth
+	for rel, sum := range local {
+		item := syncItem{
+			Name: syncPrefix + rel,
+			Path: filepath.Join(dir, filepath.FromSlash(rel)),
+		}
+		ass, ok := remote[rel]
+		switch {
+		case ok && ass.Md5 == sum:
+			continue
+		case !ok && up:
+			item.Action, item.Reason = syncActionUpload, "missing remotely"
+		case !ok && syncDelete:
+			item.Action, item.Reason = syncActionDeleteLocal, "missing remotely"
+		case ok && syncDirection == syncDirectionBoth:
+			item.Action, item.Reason = syncActionConflict, "content differs"
+		case ok && up:
+			item.Action, item.Reason = syncActionUpload, "content differs"
+		case ok && down:
+			item.Action, item.Reason, item.Remote = syncActionDownload, "content differs", ass
+		default:
+			continue
+		}
+		plan = append(plan, item)
+	}
+	for rel, ass := range remote {
+		if _, ok := local[rel]; ok {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			continue
+		}
+		item := syncItem{
+			Name:   ass.Name,
+			Path:   filepath.Join(dir, filepath.FromSlash(rel)),
+			Remote: ass,
+			Reason: "missing locally",
+		}
+		switch {
+		case down:
+			item.Action = syncActionDownload
+		case syncDelete:
+			item.Action = syncActionDeleteRemote
+		default:
+			continue
+		}
+		plan = append(plan, item)
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		return plan[i].Name < plan[j].Name
+	})
+	return plan
+}
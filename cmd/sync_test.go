@@ -0,0 +1,60 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/limrun-inc/lim/errors"
+)
+
+func TestSyncDeleteWithoutPrefix(t *testing.T) {
+	h := newHarness(t)
+	h.server.PutAsset("keep.txt", []byte("keep"))
+	h.server.PutAsset("other/team.apk", []byte("someone else's"))
+	h.writeFile("local/keep.txt", "keep")
+	if err := h.run("sync", "local", "--delete"); errors.KindOf(err) != errors.KindInvalidArgument {
+		t.Errorf("got %v, want an invalid argument error", err)
+	}
+	h.run("get", "asset")
+	if err := h.run("sync", "local", "--delete", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	h.run("get", "asset")
+	h.assertGolden("sync_delete_without_prefix")
+}
+
+func TestSyncSkipsVersions(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile("app.apk", "first")
+	if err := h.run("push", "app.apk", "--tag", "stable"); err != nil {
+		t.Fatal(err)
+	}
+	h.writeFile("app.apk", "second")
+	if err := h.run("push", "app.apk", "--tag", "stable"); err != nil {
+		t.Fatal(err)
+	}
+	h.writeFile("local/keep.txt", "keep")
+	if err := h.run("sync", "local", "--delete", "--yes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.run("sync", "down", "--direction", "down"); err != nil {
+		t.Fatal(err)
+	}
+	h.run("get", "asset")
+	h.assertGolden("sync_skips_versions")
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/release"
+	"github.com/limrun-inc/lim/version"
+)
+
+// executable returns the path of the running binary, which is the one that
+// gets replaced.
+var executable = os.Executable
+
+// UpgradeCmd represents the upgrade command
+var UpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrades lim to the latest release.",
+	Long: `The binary of the latest release for this platform is downloaded from GitHub,
+its SHA256 checksum is verified and then it replaces the running binary.
+
+lim installed with Homebrew is upgraded with brew upgrade lim instead.
+
+Examples:
+
+$ lim upgrade
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		r, err := release.Latest(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if !release.Newer(r.Version, version.Version) {
+			fmt.Fprintf(cmd.OutOrStdout(), "lim %s is the latest version\n", version.Version)
+			return nil
+		}
+		path, err := executable()
+		if err != nil {
+			return fmt.Errorf("failed to find the lim binary: %w", err)
+		}
+		if path, err = filepath.EvalSymlinks(path); err != nil {
+			return fmt.Errorf("failed to find the lim binary: %w", err)
+		}
+		if strings.Contains(filepath.ToSlash(path), "/Cellar/") {
+			return fmt.Errorf("lim is installed with Homebrew, run brew upgrade lim instead")
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Downloading lim %s\n", r.Version)
+		if err := release.Install(cmd.Context(), r, path); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Upgraded lim from %s to %s\n", version.Version, r.Version)
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(UpgradeCmd)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+	"github.com/limrun-inc/lim/version"
+)
+
+var versionOutput string
+
+func init() {
+	VersionCmd.PersistentFlags().StringVarP(&versionOutput, "output", "o", "text", "Output format, one of text or json.")
+	RootCmd.AddCommand(VersionCmd)
+}
+
+// versionInfo is the output of the version command.
+type versionInfo struct {
+	ClientVersion string `json:"clientVersion"`
+	Platform      string `json:"platform"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+}
+
+// VersionCmd represents the version command
+var VersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Prints the version of lim and of the API server.",
+	Long: `The version of the API server is printed if it's reachable and reports it.
+
+lim checks GitHub for a newer release once a day in the background and lets
+you know about it after a command finishes. Set update-check to false in the
+config, or LIM_UPDATE_CHECK=false, to turn it off. See lim upgrade.
+
+Examples:
+
+$ lim version
+$ lim version -o json
+`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if versionOutput != "text" && versionOutput != "json" {
+			return errors.Newf(errors.KindInvalidArgument, "invalid output format %q, must be one of text or json", versionOutput)
+		}
+		info := versionInfo{
+			ClientVersion: version.Version,
+			Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		}
+		serverErr := "the API server is not reachable"
+		if v, err := serverVersion(cmd.Context()); err != nil {
+			if errors.KindOf(err) == errors.KindUnsupported {
+				serverErr = "the API server doesn't report its version"
+			}
+			slog.Info("failed to get the version of the API server", "err", err)
+		} else {
+			info.ServerVersion = v
+		}
+		if versionOutput == "json" {
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(info)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Client Version: %s\n", info.ClientVersion)
+		fmt.Fprintf(cmd.OutOrStdout(), "Platform: %s\n", info.Platform)
+		if info.ServerVersion != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "Server Version: %s\n", info.ServerVersion)
+		} else {
+			fmt.Fprintf(cmd.OutOrStdout(), "Server Version: unknown, %s\n", serverErr)
+		}
+		return nil
+	},
+}
+
+// serverVersion returns the version of the API server. It doesn't wait long
+// since the version of the client is what's mostly asked for. The SDK has no
+// method for it, so GET /v1/version is called and a server that doesn't
+// serve it is reported as unsupported.
+func serverVersion(ctx context.Context) (string, error) {
+	lim, err := config.ClientFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	var out struct {
+		Version string `json:"version"`
+	}
+	if err := lim.Get(ctx, "v1/version", nil, &out); err != nil {
+		switch errors.StatusCode(err) {
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			return "", errors.WithKind(errors.KindUnsupported, err)
+		}
+		return "", err
+	}
+	if out.Version == "" {
+		return "", errors.Newf(errors.KindUnsupported, "the API server returned no version")
+	}
+	return out.Version, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/limrun-inc/lim/release"
+	"github.com/limrun-inc/lim/version"
+)
+
+func TestVersion(t *testing.T) {
+	h := newHarness(t)
+	if err := h.run("version"); err != nil {
+		t.Fatal(err)
+	}
+	// A server that reports its version.
+	next := h.handler
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/version" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"v1.2.3"}`))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+	if err := h.run("version", "-o", "json"); err != nil {
+		t.Fatal(err)
+	}
+	// The client version is printed even if the API is not reachable.
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, _, err := http.NewResponseController(w).Hijack()
+		if err == nil {
+			_ = conn.Close()
+		}
+	})
+	if err := h.runWithFlags("version", "--max-retries", "0"); err != nil {
+		t.Fatal(err)
+	}
+	h.assertGolden("version")
+}
+
+// useRelease serves a release with the given version whose binary for the
+// current platform has the given content and checksum.
+func (h *harness) useRelease(v, content, sum string) {
+	name := release.ArtifactName(runtime.GOOS, runtime.GOARCH)
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	h.t.Cleanup(ts.Close)
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(release.Release{
+			Version: v,
+			URL:     "https://github.com/limrun-inc/lim/releases/tag/" + v,
+			Assets: []release.Asset{
+				{Name: name, URL: ts.URL + "/bin"},
+				{Name: name + ".sha256", URL: ts.URL + "/sha256"},
+			},
+		})
+	})
+	mux.HandleFunc("/bin", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	})
+	mux.HandleFunc("/sha256", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(sum + "  " + name + "\n"))
+	})
+	old := release.URL
+	release.URL = ts.URL + "/latest"
+	h.t.Cleanup(func() { release.URL = old })
+}
+
+func TestUpgrade(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("upgrading is not supported on windows")
+	}
+	h := newHarness(t)
+	bin := filepath.Join(h.dir, "bin", "lim")
+	h.writeFile(bin, "old binary")
+	executable = func() (string, error) { return bin, nil }
+	t.Cleanup(func() { executable = os.Executable })
+	oldVersion := version.Version
+	version.Version = "v1.0.0"
+	t.Cleanup(func() { version.Version = oldVersion })
+
+	h.useRelease("v1.0.0", "", "")
+	if err := h.run("upgrade"); err != nil {
+		t.Fatal(err)
+	}
+	h.useRelease("v1.1.0", "new binary", "0000")
+	if err := h.run("upgrade"); err == nil {
+		t.Error("a binary with a wrong checksum was installed")
+	}
+	if b, _ := os.ReadFile(bin); string(b) != "old binary" {
+		t.Errorf("binary was replaced with %q after a failed upgrade", b)
+	}
+	sum := sha256.Sum256([]byte("new binary"))
+	h.useRelease("v1.1.0", "new binary", hex.EncodeToString(sum[:]))
+	if err := h.run("upgrade"); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(bin); string(b) != "new binary" {
+		t.Errorf("got binary %q after the upgrade, want the new one", b)
+	}
+	h.assertGolden("upgrade")
+}
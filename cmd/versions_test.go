@@ -0,0 +1,124 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/limrun-inc/lim/assets"
+)
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestPushVersions(t *testing.T) {
+	h := newHarness(t)
+	h.writeFile("app.apk", "first")
+	if err := h.run("push", "app.apk", "--tag", "stable"); err != nil {
+		t.Fatal(err)
+	}
+	// Another push took version 2 but hasn't recorded it in the index yet.
+	h.server.PutAsset("app.apk@2", []byte("concurrent"))
+	h.writeFile("app.apk", "second")
+	if err := h.run("push", "app.apk", "--tag", "stable"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	idx, err := assets.LoadIndex(ctx, h.client(), "app.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range idx.Versions {
+		got = append(got, v.Version+" "+v.Asset+" "+v.Md5)
+	}
+	want := []string{
+		"1 app.apk@1 " + md5Hex("first"),
+		"3 app.apk@3 " + md5Hex("second"),
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("got versions %q, want %q", got, want)
+	}
+
+	if err := h.run("delete", "asset", idx.Versions[0].AssetID); err != nil {
+		t.Fatal(err)
+	}
+	if idx, err = assets.LoadIndex(ctx, h.client(), "app.apk"); err != nil {
+		t.Fatal(err)
+	}
+	if len(idx.Versions) != 1 || idx.Versions[0].Version != "3" {
+		t.Errorf("got versions %+v after deleting version 1, want only 3", idx.Versions)
+	}
+}
+
+func TestRecordVersionKeepsConcurrentChanges(t *testing.T) {
+	h := newHarness(t)
+	// Someone else records a version right before the first index is saved.
+	next := h.handler
+	var once sync.Once
+	h.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path == "/v1/assets" && q.Get("nameFilter") == assets.IndexName("app.apk") && q.Get("includeDownloadUrl") != "true" {
+			once.Do(func() {
+				h.server.PutAsset(assets.IndexName("app.apk"), []byte(`{"name":"app.apk","versions":[{"version":"other","asset":"app.apk@other","assetId":"asset_other"}]}`))
+			})
+		}
+		next.ServeHTTP(w, r)
+	})
+	ctx := context.Background()
+	lim := h.client()
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 1; i <= 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- assets.RecordVersion(ctx, lim, "app.apk", assets.Version{
+				Version: strconv.Itoa(i),
+				Asset:   assets.VersionedName("app.apk", strconv.Itoa(i)),
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx, err := assets.LoadIndex(ctx, lim, "app.apk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range idx.Versions {
+		got = append(got, v.Version)
+	}
+	slices.Sort(got)
+	if want := []string{"1", "2", "3", "4", "5", "other"}; !slices.Equal(got, want) {
+		t.Errorf("got versions %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/spf13/cobra"
+
+	"github.com/limrun-inc/lim/completion"
+	"github.com/limrun-inc/lim/config"
+	"github.com/limrun-inc/lim/errors"
+	"github.com/limrun-inc/lim/viewer"
+)
+
+var (
+	noBrowser bool
+)
+
+func init() {
+	ViewCmd.PersistentFlags().BoolVar(&noBrowser, "no-browser", false, "Only print the viewer URL instead of opening it in the browser.")
+	RootCmd.AddCommand(ViewCmd)
+}
+
+// ViewCmd represents the view command
+var ViewCmd = &cobra.Command{
+	Use:   "view [ID]",
+	Short: "Streams the instance in the browser through a local viewer.",
+	Long: `The viewer does not need scrcpy or adb to be installed. Taps and key presses in
+the browser are forwarded to the instance.
+
+Examples:
+
+$ lim view <ID>
+`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completion.FirstArg(completion.Instances),
+	Annotations:       map[string]string{config.AnnotationSession: ""},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := args[0]
+		lim, err := config.ClientFromContext(cmd.Context())
+		if err != nil {
+			return err
+		}
+		ctx, cancel := config.WithTimeout(cmd.Context())
+		defer cancel()
+		var remoteURL, token string
+		switch strings.Split(id, "_")[0] {
+		case "android":
+			var i *limrun.AndroidInstance
+			i, err = lim.AndroidInstances.Get(ctx, id)
+			if err == nil {
+				remoteURL, token = i.Status.EndpointWebSocketURL, i.Status.Token
+			}
+		case "ios":
+			var i *limrun.IosInstance
+			i, err = lim.IosInstances.Get(ctx, id)
+			if err == nil {
+				remoteURL, token = i.Status.EndpointWebSocketURL, i.Status.Token
+			}
+		default:
+			return fmt.Errorf("invalid id: %s", id)
+		}
+		if err != nil {
+			if errors.IsUnauthenticated(err) {
+				if err := config.Login(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "You are logged in now")
+				return nil
+			}
+			return fmt.Errorf("failed to get instance %s: %w", id, err)
+		}
+		v, err := viewer.New(remoteURL, token)
+		if err != nil {
+			return err
+		}
+		v.Start()
+		defer func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = v.Close(ctx)
+		}()
+		fmt.Fprintf(cmd.OutOrStdout(), "Viewer is available at %s\n", v.URL())
+		if !noBrowser {
+			if err := config.OpenBrowser(v.URL()); err != nil {
+				return err
+			}
+		}
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		fmt.Fprintln(cmd.OutOrStdout(), "Press Ctrl+C to stop.")
+		sig := <-sigChan
+		fmt.Fprintf(cmd.OutOrStdout(), "Received signal %v, stopping viewer...\n", sig)
+		return nil
+	},
+}
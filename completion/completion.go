@@ -0,0 +1,303 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package completion completes the IDs and names of resources that live in
+// the Limrun API for shell completion.
+package completion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/limrun-inc/lim/assets"
+	"github.com/limrun-inc/lim/cache"
+	"github.com/limrun-inc/lim/config"
+)
+
+const (
+	// ttl is how long the fetched lists are reused since completion usually
+	// runs several times in a row while typing a command.
+	ttl = 10 * time.Second
+
+	// timeout bounds the API calls so that a slow network doesn't freeze
+	// the shell.
+	timeout = 5 * time.Second
+)
+
+// cached is the file a list of completions is kept in.
+type cached struct {
+	Key       string             `json:"key"`
+	FetchedAt time.Time          `json:"fetchedAt"`
+	Items     []cobra.Completion `json:"items"`
+}
+
+// AndroidInstances completes the IDs of Android instances that are not
+// terminated.
+func AndroidInstances(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return complete(cmd, "android", toComplete, fetchAndroidInstances)
+}
+
+// IOSInstances completes the IDs of iOS instances that are not terminated.
+func IOSInstances(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return complete(cmd, "ios", toComplete, fetchIOSInstances)
+}
+
+// Instances completes the IDs of both Android and iOS instances.
+func Instances(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	android, directive := AndroidInstances(cmd, args, toComplete)
+	if directive == cobra.ShellCompDirectiveError {
+		return nil, directive
+	}
+	ios, directive := IOSInstances(cmd, args, toComplete)
+	return append(android, ios...), directive
+}
+
+// Assets completes asset names. Comma separated lists of names, as in
+// --install-asset, are completed one name at a time.
+func Assets(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, toComplete = toComplete[:i+1], toComplete[i+1:]
+	}
+	items, directive := complete(cmd, "assets", toComplete, fetchAssets)
+	for i := range items {
+		items[i] = prefix + items[i]
+	}
+	return items, directive
+}
+
+// AssetIDs completes asset IDs with the asset names as descriptions, for
+// commands that tell assets and instances apart by their IDs.
+func AssetIDs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return complete(cmd, "asset-ids", toComplete, fetchAssetIDs)
+}
+
+// IDs completes the IDs of instances and assets.
+func IDs(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	instances, directive := Instances(cmd, args, toComplete)
+	if directive == cobra.ShellCompDirectiveError {
+		return nil, directive
+	}
+	ids, directive := AssetIDs(cmd, args, toComplete)
+	return append(instances, ids...), directive
+}
+
+// Regions completes the regions the existing instances are in.
+func Regions(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return complete(cmd, "regions", toComplete, fetchRegions)
+}
+
+// FirstArg completes the first positional argument with f and nothing after
+// it, for commands that take a single ID.
+func FirstArg(f cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return f(cmd, args, toComplete)
+	}
+}
+
+// ThenFiles completes the first positional argument with f and local files
+// after it, for commands that take an ID followed by files.
+func ThenFiles(f cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveDefault
+		}
+		return f(cmd, args, toComplete)
+	}
+}
+
+// complete returns the items of the given kind that start with toComplete,
+// from the cache if it's fresh or from the API otherwise.
+func complete(cmd *cobra.Command, kind, toComplete string, fetch func(context.Context, limrun.Client) ([]cobra.Completion, error)) ([]cobra.Completion, cobra.ShellCompDirective) {
+	lim, err := client(cmd)
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil, cobra.ShellCompDirectiveError
+	}
+	key := cacheKey()
+	path := filepath.Join(cache.Dir(), "completion", kind+".json")
+	items, ok := load(path, key)
+	if !ok {
+		ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+		defer cancel()
+		if items, err = fetch(ctx, lim); err != nil {
+			cobra.CompErrorln(err.Error())
+			return nil, cobra.ShellCompDirectiveError
+		}
+		save(path, cached{Key: key, FetchedAt: time.Now(), Items: items})
+	}
+	var matching []cobra.Completion
+	for _, item := range items {
+		if strings.HasPrefix(item, toComplete) {
+			matching = append(matching, item)
+		}
+	}
+	return matching, cobra.ShellCompDirectiveNoFileComp
+}
+
+// client returns the API client. The persistent pre-run of the root command
+// doesn't set it up for completions, so it's run here with the command being
+// completed to get the configuration and the client the same way commands
+// get them.
+func client(cmd *cobra.Command) (limrun.Client, error) {
+	if lim, err := config.ClientFromContext(cmd.Context()); err == nil {
+		return lim, nil
+	}
+	if root := cmd.Root(); root.PersistentPreRunE != nil {
+		if err := root.PersistentPreRunE(cmd, nil); err != nil {
+			return limrun.Client{}, err
+		}
+	}
+	return config.ClientFromContext(cmd.Context())
+}
+
+// cacheKey identifies the account the cached lists belong to so that
+// switching the endpoint or the API key doesn't complete stale items.
+func cacheKey() string {
+	sum := sha256.Sum256([]byte(viper.GetString(config.ConfigKeyAPIEndpoint) + "\n" + viper.GetString(config.ConfigKeyAPIKey)))
+	return hex.EncodeToString(sum[:8])
+}
+
+func load(path, key string) ([]cobra.Completion, bool) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var c cached
+	if err := json.Unmarshal(b, &c); err != nil || c.Key != key || time.Since(c.FetchedAt) > ttl {
+		return nil, false
+	}
+	return c.Items, true
+}
+
+// save writes the cache file. Errors are ignored since the cache is only
+// an optimization.
+func save(path string, c cached) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, b, 0600)
+}
+
+func fetchAndroidInstances(ctx context.Context, lim limrun.Client) ([]cobra.Completion, error) {
+	fetched, err := lim.AndroidInstances.List(ctx, limrun.AndroidInstanceListParams{})
+	if err != nil {
+		return nil, err
+	}
+	var items []cobra.Completion
+	for _, i := range *fetched {
+		if i.Status.State == "terminated" {
+			continue
+		}
+		items = append(items, cobra.CompletionWithDesc(i.Metadata.ID, describe(i.Metadata.DisplayName, i.Status.State)))
+	}
+	return items, nil
+}
+
+func fetchIOSInstances(ctx context.Context, lim limrun.Client) ([]cobra.Completion, error) {
+	fetched, err := lim.IosInstances.List(ctx, limrun.IosInstanceListParams{})
+	if err != nil {
+		return nil, err
+	}
+	var items []cobra.Completion
+	for _, i := range *fetched {
+		if i.Status.State == "terminated" {
+			continue
+		}
+		items = append(items, cobra.CompletionWithDesc(i.Metadata.ID, describe(i.Metadata.DisplayName, i.Status.State)))
+	}
+	return items, nil
+}
+
+// fetchAssets completes asset names with their IDs as descriptions. The
+// indexes of versioned assets are internal and left out.
+func fetchAssets(ctx context.Context, lim limrun.Client) ([]cobra.Completion, error) {
+	fetched, err := lim.Assets.List(ctx, limrun.AssetListParams{})
+	if err != nil {
+		return nil, err
+	}
+	var items []cobra.Completion
+	for _, ass := range *fetched {
+		if assets.IsIndex(ass.Name) {
+			continue
+		}
+		items = append(items, cobra.CompletionWithDesc(ass.Name, ass.ID))
+	}
+	return items, nil
+}
+
+func fetchAssetIDs(ctx context.Context, lim limrun.Client) ([]cobra.Completion, error) {
+	fetched, err := lim.Assets.List(ctx, limrun.AssetListParams{})
+	if err != nil {
+		return nil, err
+	}
+	var items []cobra.Completion
+	for _, ass := range *fetched {
+		if assets.IsIndex(ass.Name) {
+			continue
+		}
+		items = append(items, cobra.CompletionWithDesc(ass.ID, ass.Name))
+	}
+	return items, nil
+}
+
+func fetchRegions(ctx context.Context, lim limrun.Client) ([]cobra.Completion, error) {
+	var regions []string
+	android, err := lim.AndroidInstances.List(ctx, limrun.AndroidInstanceListParams{})
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range *android {
+		regions = append(regions, i.Spec.Region)
+	}
+	// iOS may not be enabled for the organization, in which case the
+	// regions of Android are all there is.
+	if ios, err := lim.IosInstances.List(ctx, limrun.IosInstanceListParams{}); err == nil {
+		for _, i := range *ios {
+			regions = append(regions, i.Spec.Region)
+		}
+	}
+	slices.Sort(regions)
+	regions = slices.Compact(regions)
+	return slices.DeleteFunc(regions, func(r string) bool { return r == "" }), nil
+}
+
+// describe joins the display name and the state of an instance, either of
+// which may be empty.
+func describe(name, state string) string {
+	if name == "" {
+		return state
+	}
+	return name + " (" + state + ")"
+}
@@ -32,6 +32,21 @@ const (
 	ConfigKeyAPIKey          = "api-key"
 	ConfigKeyAPIEndpoint     = "api-endpoint"
 	ConfigKeyConsoleEndpoint = "console-endpoint"
+	ConfigKeyCacheDir        = "cache-dir"
+	ConfigKeyErrorFormat     = "error-format"
+	ConfigKeyVerbosity       = "verbosity"
+	ConfigKeyDebugHTTP       = "debug-http"
+	ConfigKeyLogFormat       = "log-format"
+	ConfigKeyTimeout         = "timeout"
+	ConfigKeyMaxRetries      = "max-retries"
+	ConfigKeyRetryBackoff    = "retry-backoff"
+	ConfigKeyHTTPSProxy      = "https-proxy"
+	ConfigKeyNoProxy         = "no-proxy"
+	ConfigKeyCAFile          = "ca-file"
+	ConfigKeyClientCert      = "client-cert"
+	ConfigKeyClientKey       = "client-key"
+	ConfigKeyUpdateCheck     = "update-check"
+	ConfigKeyAliases         = "aliases"
 )
 
 func Login(ctx context.Context) error {
@@ -78,7 +93,7 @@ func Login(ctx context.Context) error {
 	vals := u.Query()
 	vals.Set("user-agent", "lim/"+version.Version)
 	u.RawQuery = vals.Encode()
-	if err := openBrowser(u.String()); err != nil {
+	if err := OpenBrowser(u.String()); err != nil {
 		return err
 	}
 	<-loggedIn
@@ -88,7 +103,8 @@ func Login(ctx context.Context) error {
 	return nil
 }
 
-func openBrowser(url string) error {
+// OpenBrowser opens the given URL in the default browser of the user.
+func OpenBrowser(url string) error {
 	switch os := runtime.GOOS; os {
 	case "linux":
 		return exec.Command("xdg-open", url).Start()
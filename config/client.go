@@ -0,0 +1,63 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"errors"
+
+	limrun "github.com/limrun-inc/go-sdk"
+	"github.com/spf13/viper"
+)
+
+// AnnotationSession is the annotation of commands that keep a session open
+// until they're stopped, e.g. a tunnel. --timeout doesn't limit them as a
+// whole but only the API calls they make with a context from WithTimeout.
+const AnnotationSession = "lim/session"
+
+// clientKey is the context key of the API client.
+type clientKey struct{}
+
+// ErrNoClient is returned when a command runs without the API client that
+// the root command sets up.
+var ErrNoClient = errors.New("no Limrun API client is configured")
+
+// WithClient returns a copy of the context that carries the API client.
+func WithClient(ctx context.Context, lim limrun.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, lim)
+}
+
+// ClientFromContext returns the API client carried by the context.
+func ClientFromContext(ctx context.Context) (limrun.Client, error) {
+	if ctx == nil {
+		return limrun.Client{}, ErrNoClient
+	}
+	lim, ok := ctx.Value(clientKey{}).(limrun.Client)
+	if !ok {
+		return limrun.Client{}, ErrNoClient
+	}
+	return lim, nil
+}
+
+// WithTimeout returns a copy of the context that is done after the duration
+// given with --timeout, or the context as is if there is none.
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d := viper.GetDuration(ConfigKeyTimeout); d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return ctx, func() {}
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/viper"
+)
+
+// defaultTransport is the transport of the standard library before
+// ConfigureNetwork replaces it.
+var defaultTransport = http.DefaultTransport.(*http.Transport).Clone()
+
+// ConfigureNetwork applies the proxy, CA and client certificate settings to
+// http.DefaultTransport and websocket.DefaultDialer. The API client, the
+// uploads and downloads through signed URLs and the WebSocket connections
+// to instances, e.g. the ADB tunnel, all go through them.
+func ConfigureNetwork() error {
+	tlsConfig, err := tlsConfig(
+		viper.GetString(ConfigKeyCAFile),
+		viper.GetString(ConfigKeyClientCert),
+		viper.GetString(ConfigKeyClientKey),
+	)
+	if err != nil {
+		return err
+	}
+	proxy, err := proxyFunc(viper.GetString(ConfigKeyHTTPSProxy), viper.GetString(ConfigKeyNoProxy))
+	if err != nil {
+		return err
+	}
+	t := defaultTransport.Clone()
+	t.Proxy = proxy
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig
+	}
+	http.DefaultTransport = t
+	websocket.DefaultDialer.Proxy = proxy
+	websocket.DefaultDialer.TLSClientConfig = tlsConfig
+	return nil
+}
+
+// tlsConfig returns the TLS config that trusts the CAs in caFile in
+// addition to the ones of the system and presents the client certificate,
+// or nil if none is given.
+func tlsConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	c := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM encoded certificates in CA file %s", caFile)
+		}
+		c.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, fmt.Errorf("both %s and %s need to be given for a client certificate", ConfigKeyClientCert, ConfigKeyClientKey)
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		c.Certificates = []tls.Certificate{cert}
+	}
+	return c, nil
+}
+
+// proxyFunc returns the proxy selection that sends HTTPS and secure
+// WebSocket requests through httpsProxy unless their host matches noProxy.
+// The environment, e.g. HTTPS_PROXY and NO_PROXY, is used for what's not
+// configured.
+func proxyFunc(httpsProxy, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	if httpsProxy == "" && noProxy == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	var proxyURL *url.URL
+	if httpsProxy != "" {
+		var err error
+		if proxyURL, err = url.Parse(httpsProxy); err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid %s %q, must be a URL like http://proxy.example.com:3128", ConfigKeyHTTPSProxy, httpsProxy)
+		}
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(noProxy, req.URL) {
+			return nil, nil
+		}
+		if proxyURL != nil && (req.URL.Scheme == "https" || req.URL.Scheme == "wss") {
+			return proxyURL, nil
+		}
+		return http.ProxyFromEnvironment(req)
+	}, nil
+}
+
+// bypassProxy returns whether the URL matches one of the comma separated
+// entries of noProxy. An entry is *, a domain that matches its subdomains
+// too, an IP address or a CIDR range, optionally followed by a port.
+func bypassProxy(noProxy string, u *url.URL) bool {
+	host, port := u.Hostname(), u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "ws": "80", "https": "443", "wss": "443"}[u.Scheme]
+	}
+	ip := net.ParseIP(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entry = h
+		}
+		if entryIP := net.ParseIP(entry); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		domain := strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		host := strings.ToLower(host)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
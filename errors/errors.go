@@ -14,22 +14,242 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package errors classifies the errors of commands into kinds that have
+// stable exit codes so that scripts can branch on the kind of a failure.
+//
+// Exit codes:
+//
+//	0  success
+//	1  unknown, any error that isn't classified
+//	2  invalid_argument, e.g. a missing argument, an unknown flag or command
+//	3  unauthenticated, the API key is missing or invalid
+//	4  not_found, the instance or asset doesn't exist
+//	5  conflict, the resource exists already or was changed concurrently
+//	6  quota_exceeded, a limit of the organization is reached or the API
+//	   rate limits the requests
+//	7  timeout, a deadline passed before the operation completed
+//	8  network, the API couldn't be reached
+//	9  unsupported, the API server doesn't support the operation
 package errors
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
-	limrun "github.com/limrun-inc/go-sdk"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
 	"strings"
+	"syscall"
+
+	limrun "github.com/limrun-inc/go-sdk"
 )
 
+// Kind is the kind of an error.
+type Kind string
+
+const (
+	KindUnknown         Kind = "unknown"
+	KindInvalidArgument Kind = "invalid_argument"
+	KindUnauthenticated Kind = "unauthenticated"
+	KindNotFound        Kind = "not_found"
+	KindConflict        Kind = "conflict"
+	KindQuotaExceeded   Kind = "quota_exceeded"
+	KindTimeout         Kind = "timeout"
+	KindNetwork         Kind = "network"
+	KindUnsupported     Kind = "unsupported"
+)
+
+// ExitCode returns the exit code of the kind.
+func (k Kind) ExitCode() int {
+	switch k {
+	case KindInvalidArgument:
+		return 2
+	case KindUnauthenticated:
+		return 3
+	case KindNotFound:
+		return 4
+	case KindConflict:
+		return 5
+	case KindQuotaExceeded:
+		return 6
+	case KindTimeout:
+		return 7
+	case KindNetwork:
+		return 8
+	case KindUnsupported:
+		return 9
+	default:
+		return 1
+	}
+}
+
+// Error is an error of a known kind.
+type Error struct {
+	Kind Kind
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Kinded is implemented by errors that know their kind, e.g. the ones of the
+// assets package.
+type Kinded interface {
+	Kind() Kind
+}
+
+// Newf returns an error of the given kind formatted like fmt.Errorf.
+func Newf(kind Kind, format string, args ...any) error {
+	return &Error{Kind: kind, Err: fmt.Errorf(format, args...)}
+}
+
+// WithKind returns the error marked as the given kind. nil stays nil.
+func WithKind(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// KindOf returns the kind of the error, looking at the errors it wraps,
+// the status code and message of API errors and network failures.
+func KindOf(err error) Kind {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	var k Kinded
+	if errors.As(err, &k) {
+		return k.Kind()
+	}
+	var apiErr *limrun.Error
+	if errors.As(err, &apiErr) {
+		return apiKind(apiErr)
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) {
+		return KindTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return KindTimeout
+	}
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return KindNetwork
+	}
+	return KindUnknown
+}
+
+// ExitError is the exit code of a program that lim ran in its place, e.g. a
+// plugin. The code is passed on as is and the error isn't printed since the
+// program reports its own errors.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// ExitCode returns the exit code of the error, 0 for nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exit *ExitError
+	if errors.As(err, &exit) {
+		return exit.Code
+	}
+	return KindOf(err).ExitCode()
+}
+
+// codes maps the codes the API prefixes its error messages with, e.g.
+// "unauthenticated: invalid API key", to kinds.
+var codes = map[string]Kind{
+	"unauthenticated":     KindUnauthenticated,
+	"not_found":           KindNotFound,
+	"already_exists":      KindConflict,
+	"aborted":             KindConflict,
+	"failed_precondition": KindConflict,
+	"resource_exhausted":  KindQuotaExceeded,
+	"invalid_argument":    KindInvalidArgument,
+	"out_of_range":        KindInvalidArgument,
+	"deadline_exceeded":   KindTimeout,
+	"unavailable":         KindNetwork,
+}
+
+// apiKind returns the kind of an error response of the API from the code in
+// its message, or its status code if the message has none.
+func apiKind(err *limrun.Error) Kind {
+	if code, _, ok := strings.Cut(Message(err), ":"); ok {
+		if k, ok := codes[strings.TrimSpace(code)]; ok {
+			return k
+		}
+	}
+	switch err.StatusCode {
+	case http.StatusUnauthorized:
+		return KindUnauthenticated
+	case http.StatusNotFound, http.StatusGone:
+		return KindNotFound
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return KindConflict
+	case http.StatusPaymentRequired, http.StatusTooManyRequests:
+		return KindQuotaExceeded
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return KindInvalidArgument
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return KindTimeout
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return KindNetwork
+	}
+	return KindUnknown
+}
+
+// Message returns the message in the body of an API error response, or an
+// empty string if it has none.
+func Message(err *limrun.Error) string {
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err.RawJSON() == "" || json.Unmarshal([]byte(err.RawJSON()), &body) != nil {
+		return ""
+	}
+	return body.Message
+}
+
 // IsUnauthenticated returns whether the API error means
 // unauthenticated.
 func IsUnauthenticated(err error) bool {
+	return KindOf(err) == KindUnauthenticated
+}
+
+// StatusCode returns the HTTP status code of the API error the error wraps,
+// or 0 if it doesn't wrap one.
+func StatusCode(err error) int {
+	var apiErr *limrun.Error
+	if !errors.As(err, &apiErr) {
+		return 0
+	}
+	return apiErr.StatusCode
+}
+
+// RequestID returns the ID the API assigned to the failed request, or an
+// empty string if the error doesn't wrap an API error with one.
+func RequestID(err error) string {
 	var apiErr *limrun.Error
-	ok := errors.As(err, &apiErr)
-	if !ok {
-		return false
+	if !errors.As(err, &apiErr) || apiErr.Response == nil {
+		return ""
 	}
-	b := string(apiErr.DumpResponse(true))
-	return strings.Contains(b, `{"message":"unauthenticated:`)
+	return apiErr.Response.Header.Get("X-Request-Id")
 }
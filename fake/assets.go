@@ -0,0 +1,226 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// asset is an asset and its content once it's uploaded.
+type asset struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	Content   []byte
+	Md5       string
+	UpdatedAt time.Time
+}
+
+// assetJSON is the asset as the API returns it.
+type assetJSON struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Md5               string    `json:"md5,omitempty"`
+	SignedDownloadURL string    `json:"signedDownloadUrl,omitempty"`
+	SignedUploadURL   string    `json:"signedUploadUrl,omitempty"`
+	CreatedAt         time.Time `json:"createdAt"`
+}
+
+// PutAsset adds an asset with the given content, replacing the content of
+// the asset with the same name if it exists, and returns its ID. It's meant
+// for seeding the server in tests.
+func (s *Server) PutAsset(name string, content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.assetByName(name)
+	if a == nil {
+		a = &asset{ID: s.newID("asset"), Name: name, CreatedAt: s.now()}
+		s.assets[a.ID] = a
+	}
+	a.setContent(content, s.now())
+	return a.ID
+}
+
+func (a *asset) setContent(content []byte, now time.Time) {
+	a.Content = content
+	a.Md5 = fmt.Sprintf("%x", md5.Sum(content))
+	a.UpdatedAt = now
+}
+
+// assetByName returns the asset with the given name. mu must be held.
+func (s *Server) assetByName(name string) *asset {
+	for _, a := range s.assets {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// assetToJSON returns the asset with the signed URLs that are asked for in the
+// query. mu must be held.
+func (s *Server) assetToJSON(r *http.Request, a *asset, download, upload bool) assetJSON {
+	j := assetJSON{
+		ID:        a.ID,
+		Name:      a.Name,
+		Md5:       a.Md5,
+		CreatedAt: a.CreatedAt,
+	}
+	if download && a.Content != nil {
+		j.SignedDownloadURL = s.signedURL(r, http.MethodGet, a.ID, defaultURLExpiry)
+	}
+	if upload {
+		j.SignedUploadURL = s.signedURL(r, http.MethodPut, a.ID, defaultURLExpiry)
+	}
+	return j
+}
+
+func (s *Server) signedURL(r *http.Request, method, id string, expiry time.Duration) string {
+	date := s.now().Format("20060102T150405Z")
+	expires := strconv.Itoa(int(expiry.Seconds()))
+	q := url.Values{}
+	q.Set("X-Goog-Date", date)
+	q.Set("X-Goog-Expires", expires)
+	q.Set("X-Goog-Signature", sign(method, id, date, expires))
+	return baseURL(r, "http") + "/storage/" + id + "?" + q.Encode()
+}
+
+func (s *Server) listAssets(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := q.Get("nameFilter")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []assetJSON{}
+	for _, a := range s.assets {
+		if filter != "" && !strings.Contains(a.Name, filter) {
+			continue
+		}
+		list = append(list, s.assetToJSON(r, a, q.Get("includeDownloadUrl") == "true", q.Get("includeUploadUrl") == "true"))
+	}
+	slices.SortFunc(list, func(a, b assetJSON) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *Server) getAsset(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.assets[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, s.assetToJSON(r, a, q.Get("includeDownloadUrl") == "true", q.Get("includeUploadUrl") == "true"))
+}
+
+func (s *Server) getOrNewAsset(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.assetByName(body.Name)
+	if a == nil {
+		a = &asset{ID: s.newID("asset"), Name: body.Name, CreatedAt: s.now()}
+		s.assets[a.ID] = a
+	}
+	writeJSON(w, http.StatusOK, s.assetToJSON(r, a, true, true))
+}
+
+func (s *Server) deleteAsset(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.PathValue("id")
+	if _, ok := s.assets[id]; !ok {
+		writeError(w, http.StatusNotFound, "asset not found")
+		return
+	}
+	delete(s.assets, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// storage serves the signed URLs. Downloads support range requests.
+func (s *Server) storage(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	q := r.URL.Query()
+	date, expires := q.Get("X-Goog-Date"), q.Get("X-Goog-Expires")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	method := r.Method
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if q.Get("X-Goog-Signature") != sign(method, id, date, expires) {
+		http.Error(w, "signature does not match", http.StatusForbidden)
+		return
+	}
+	signedAt, err := time.Parse("20060102T150405Z", date)
+	secs, err2 := strconv.Atoi(expires)
+	if err != nil || err2 != nil || s.now().After(signedAt.Add(time.Duration(secs)*time.Second)) {
+		http.Error(w, "signed URL expired", http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodPut {
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		a, ok := s.assets[id]
+		if !ok {
+			http.Error(w, "asset not found", http.StatusNotFound)
+			return
+		}
+		a.setContent(content, s.now())
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	s.mu.Lock()
+	a, ok := s.assets[id]
+	var content []byte
+	var updatedAt time.Time
+	if ok {
+		content, updatedAt = a.Content, a.UpdatedAt
+	}
+	s.mu.Unlock()
+	if !ok || content == nil {
+		http.Error(w, "no such object", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, "", updatedAt, bytes.NewReader(content))
+}
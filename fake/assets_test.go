@@ -0,0 +1,134 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAssetUploadAndDownload(t *testing.T) {
+	ts := newTestServer(t)
+	status, b := ts.do(http.MethodPut, "/v1/assets", map[string]string{"name": "app.apk"})
+	if status != http.StatusOK {
+		t.Fatalf("get or new: got status %d: %s", status, b)
+	}
+	var created assetJSON
+	ts.decode(b, &created)
+	// There is nothing to download until the content is uploaded.
+	if created.ID == "" || created.SignedUploadURL == "" || created.SignedDownloadURL != "" || created.Md5 != "" {
+		t.Fatalf("get or new: got %+v, want a new asset with only an upload URL", created)
+	}
+	if status, b := ts.do(http.MethodPut, created.SignedUploadURL, []byte("fake apk")); status != http.StatusOK {
+		t.Fatalf("upload: got status %d: %s", status, b)
+	}
+
+	status, b = ts.do(http.MethodGet, "/v1/assets/"+created.ID+"?includeDownloadUrl=true", nil)
+	if status != http.StatusOK {
+		t.Fatalf("get: got status %d: %s", status, b)
+	}
+	var got assetJSON
+	ts.decode(b, &got)
+	if want := fmt.Sprintf("%x", md5.Sum([]byte("fake apk"))); got.Md5 != want {
+		t.Errorf("get: got MD5 %q, want %q", got.Md5, want)
+	}
+	if got.SignedUploadURL != "" {
+		t.Errorf("get: got an upload URL without asking for it")
+	}
+	if status, b := ts.do(http.MethodGet, got.SignedDownloadURL, nil); status != http.StatusOK || string(b) != "fake apk" {
+		t.Errorf("download: got status %d and %q", status, b)
+	}
+	if status, b := ts.do(http.MethodGet, got.SignedDownloadURL, nil, "Range", "bytes=5-"); status != http.StatusPartialContent || string(b) != "apk" {
+		t.Errorf("range download: got status %d and %q", status, b)
+	}
+
+	// The same name returns the same asset.
+	status, b = ts.do(http.MethodPut, "/v1/assets", map[string]string{"name": "app.apk"})
+	var again assetJSON
+	ts.decode(b, &again)
+	if status != http.StatusOK || again.ID != created.ID || again.Md5 != got.Md5 {
+		t.Errorf("get or new again: got status %d and %+v, want %s", status, again, created.ID)
+	}
+	if status, _ := ts.do(http.MethodPut, "/v1/assets", map[string]string{}); status != http.StatusBadRequest {
+		t.Errorf("get or new without name: got status %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestAssetSignedURLs(t *testing.T) {
+	ts := newTestServer(t)
+	ts.PutAsset("app.apk", []byte("fake apk"))
+	status, b := ts.do(http.MethodGet, "/v1/assets?includeDownloadUrl=true", nil)
+	if status != http.StatusOK {
+		t.Fatalf("list: got status %d: %s", status, b)
+	}
+	var list []assetJSON
+	ts.decode(b, &list)
+	if len(list) != 1 {
+		t.Fatalf("list: got %d assets, want 1", len(list))
+	}
+	u := list[0].SignedDownloadURL
+	tampered := strings.Replace(u, "X-Goog-Expires=3600", "X-Goog-Expires=7200", 1)
+	if status, _ := ts.do(http.MethodGet, tampered, nil); status != http.StatusForbidden {
+		t.Errorf("tampered URL: got status %d, want %d", status, http.StatusForbidden)
+	}
+	// A download URL can't be used to upload.
+	if status, _ := ts.do(http.MethodPut, u, []byte("other")); status != http.StatusForbidden {
+		t.Errorf("upload with download URL: got status %d, want %d", status, http.StatusForbidden)
+	}
+	ts.now = ts.now.Add(defaultURLExpiry + time.Second)
+	if status, _ := ts.do(http.MethodGet, u, nil); status != http.StatusBadRequest {
+		t.Errorf("expired URL: got status %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestAssetListAndDelete(t *testing.T) {
+	ts := newTestServer(t)
+	id := ts.PutAsset("build/app.apk", []byte("apk"))
+	ts.PutAsset("build/app.ipa", []byte("ipa"))
+	ts.PutAsset("notes.txt", []byte("notes"))
+
+	status, b := ts.do(http.MethodGet, "/v1/assets?nameFilter=build/", nil)
+	if status != http.StatusOK {
+		t.Fatalf("list: got status %d: %s", status, b)
+	}
+	var list []assetJSON
+	ts.decode(b, &list)
+	var names []string
+	for _, a := range list {
+		names = append(names, a.Name)
+		if a.SignedDownloadURL != "" || a.SignedUploadURL != "" {
+			t.Errorf("list: %s has signed URLs without asking for them", a.Name)
+		}
+	}
+	if strings.Join(names, ",") != "build/app.apk,build/app.ipa" {
+		t.Errorf("list: got %v, want the build/ assets sorted by name", names)
+	}
+
+	if status, b := ts.do(http.MethodDelete, "/v1/assets/"+id, nil); status != http.StatusNoContent {
+		t.Fatalf("delete: got status %d: %s", status, b)
+	}
+	if status, _ := ts.do(http.MethodGet, "/v1/assets/"+id, nil); status != http.StatusNotFound {
+		t.Errorf("get deleted: got status %d, want %d", status, http.StatusNotFound)
+	}
+	if status, _ := ts.do(http.MethodDelete, "/v1/assets/"+id, nil); status != http.StatusNotFound {
+		t.Errorf("delete again: got status %d, want %d", status, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	kindAndroid = "android"
+	kindIOS     = "ios"
+)
+
+// States of an instance.
+const (
+	stateCreating   = "creating"
+	stateReady      = "ready"
+	stateTerminated = "terminated"
+)
+
+// instance is an Android or iOS instance.
+type instance struct {
+	Kind         string
+	ID           string
+	DisplayName  string
+	Labels       map[string]string
+	Region       string
+	CreatedAt    time.Time
+	TerminatedAt time.Time
+	Token        string
+	Terminated   bool
+}
+
+// instanceJSON is the instance as the API returns it.
+type instanceJSON struct {
+	Metadata struct {
+		ID             string            `json:"id"`
+		CreatedAt      time.Time         `json:"createdAt"`
+		OrganizationID string            `json:"organizationId"`
+		DisplayName    string            `json:"displayName,omitempty"`
+		Labels         map[string]string `json:"labels,omitempty"`
+		TerminatedAt   *time.Time        `json:"terminatedAt,omitempty"`
+	} `json:"metadata"`
+	Spec struct {
+		InactivityTimeout string `json:"inactivityTimeout"`
+		Region            string `json:"region"`
+	} `json:"spec"`
+	Status struct {
+		Token                string `json:"token"`
+		State                string `json:"state"`
+		AdbWebSocketURL      string `json:"adbWebSocketUrl,omitempty"`
+		EndpointWebSocketURL string `json:"endpointWebSocketUrl,omitempty"`
+	} `json:"status"`
+}
+
+// newInstanceRequest is the body of the create request, shared by Android
+// and iOS.
+type newInstanceRequest struct {
+	Metadata struct {
+		DisplayName string            `json:"displayName"`
+		Labels      map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		Region        string `json:"region"`
+		InitialAssets []struct {
+			Kind       string   `json:"kind"`
+			Source     string   `json:"source"`
+			AssetName  string   `json:"assetName"`
+			AssetNames []string `json:"assetNames"`
+			URL        string   `json:"url"`
+			URLs       []string `json:"urls"`
+		} `json:"initialAssets"`
+	} `json:"spec"`
+}
+
+// state returns the state of the instance at the given time.
+func (s *Server) state(i *instance, now time.Time) string {
+	switch {
+	case i.Terminated:
+		return stateTerminated
+	case now.Before(i.CreatedAt.Add(s.ReadyAfter)):
+		return stateCreating
+	}
+	return stateReady
+}
+
+// instanceToJSON returns the instance as the API does. mu must be held.
+func (s *Server) instanceToJSON(r *http.Request, i *instance) instanceJSON {
+	var j instanceJSON
+	j.Metadata.ID = i.ID
+	j.Metadata.CreatedAt = i.CreatedAt
+	j.Metadata.OrganizationID = "org_fake"
+	j.Metadata.DisplayName = i.DisplayName
+	j.Metadata.Labels = i.Labels
+	if i.Terminated {
+		t := i.TerminatedAt
+		j.Metadata.TerminatedAt = &t
+	}
+	j.Spec.InactivityTimeout = "10m0s"
+	j.Spec.Region = i.Region
+	j.Status.Token = i.Token
+	j.Status.State = s.state(i, s.now())
+	if j.Status.State == stateReady {
+		base := baseURL(r, "ws")
+		if i.Kind == kindAndroid {
+			j.Status.AdbWebSocketURL = base + "/adb/" + i.ID
+		}
+		j.Status.EndpointWebSocketURL = base + "/endpoint/" + i.ID
+	}
+	return j
+}
+
+func (s *Server) newInstance(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req newInstanceRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %s", err))
+				return
+			}
+		}
+		s.mu.Lock()
+		i := &instance{
+			Kind:        kind,
+			ID:          s.newID(kind),
+			DisplayName: req.Metadata.DisplayName,
+			Labels:      req.Metadata.Labels,
+			Region:      req.Spec.Region,
+			CreatedAt:   s.now(),
+		}
+		if i.Region == "" {
+			i.Region = "fake-region"
+		}
+		i.Token = fmt.Sprintf("token-%d", s.seq)
+		for _, a := range req.Spec.InitialAssets {
+			names := a.AssetNames
+			if a.AssetName != "" {
+				names = append(names, a.AssetName)
+			}
+			for _, n := range names {
+				if ass := s.assetByName(n); ass == nil || ass.Content == nil {
+					s.mu.Unlock()
+					writeError(w, http.StatusBadRequest, fmt.Sprintf("asset %s is not uploaded", n))
+					return
+				}
+			}
+		}
+		s.instances[i.ID] = i
+		s.mu.Unlock()
+		if r.URL.Query().Get("wait") == "true" && s.ReadyAfter > 0 {
+			select {
+			case <-time.After(s.ReadyAfter):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writeJSON(w, http.StatusOK, s.instanceToJSON(r, i))
+	}
+}
+
+func (s *Server) listInstances(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		selector := map[string]string{}
+		for _, pair := range strings.Split(q.Get("labelSelector"), ",") {
+			if k, v, ok := strings.Cut(pair, "="); ok {
+				selector[k] = v
+			}
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		now := s.now()
+		var ids []string
+		for id, i := range s.instances {
+			if i.Kind != kind {
+				continue
+			}
+			if state := q.Get("state"); state != "" && s.state(i, now) != state {
+				continue
+			}
+			if region := q.Get("region"); region != "" && i.Region != region {
+				continue
+			}
+			matches := true
+			for k, v := range selector {
+				if i.Labels[k] != v {
+					matches = false
+				}
+			}
+			if matches {
+				ids = append(ids, id)
+			}
+		}
+		slices.Sort(ids)
+		list := []instanceJSON{}
+		for _, id := range ids {
+			list = append(list, s.instanceToJSON(r, s.instances[id]))
+		}
+		writeJSON(w, http.StatusOK, list)
+	}
+}
+
+func (s *Server) getInstance(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i, ok := s.instances[r.PathValue("id")]
+		if !ok || i.Kind != kind {
+			writeError(w, http.StatusNotFound, "instance not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, s.instanceToJSON(r, i))
+	}
+}
+
+func (s *Server) deleteInstance(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		i, ok := s.instances[r.PathValue("id")]
+		if !ok || i.Kind != kind {
+			writeError(w, http.StatusNotFound, "instance not found")
+			return
+		}
+		if !i.Terminated {
+			i.Terminated = true
+			i.TerminatedAt = s.now()
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
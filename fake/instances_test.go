@@ -0,0 +1,131 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstanceLifecycle(t *testing.T) {
+	ts := newTestServer(t)
+	ts.ReadyAfter = time.Minute
+	status, b := ts.do(http.MethodPost, "/v1/android_instances", map[string]any{
+		"metadata": map[string]any{"labels": map[string]string{"team": "qa"}},
+	})
+	if status != http.StatusOK {
+		t.Fatalf("create: got status %d: %s", status, b)
+	}
+	var created instanceJSON
+	ts.decode(b, &created)
+	if created.Status.State != stateCreating || created.Spec.Region != "fake-region" || created.Status.AdbWebSocketURL != "" {
+		t.Errorf("create: got %+v, want a creating instance in the default region without URLs", created)
+	}
+
+	ts.now = ts.now.Add(time.Minute)
+	status, b = ts.do(http.MethodGet, "/v1/android_instances/"+created.Metadata.ID, nil)
+	if status != http.StatusOK {
+		t.Fatalf("get: got status %d: %s", status, b)
+	}
+	var ready instanceJSON
+	ts.decode(b, &ready)
+	if ready.Status.State != stateReady {
+		t.Errorf("get: got state %s, want %s", ready.Status.State, stateReady)
+	}
+	if !strings.HasPrefix(ready.Status.AdbWebSocketURL, "ws://") || !strings.HasSuffix(ready.Status.AdbWebSocketURL, "/adb/"+created.Metadata.ID) {
+		t.Errorf("get: got ADB URL %q", ready.Status.AdbWebSocketURL)
+	}
+
+	// Instances of one kind are not found through the other.
+	if status, _ := ts.do(http.MethodGet, "/v1/ios_instances/"+created.Metadata.ID, nil); status != http.StatusNotFound {
+		t.Errorf("get as iOS: got status %d, want %d", status, http.StatusNotFound)
+	}
+
+	if status, b := ts.do(http.MethodDelete, "/v1/android_instances/"+created.Metadata.ID, nil); status != http.StatusNoContent {
+		t.Fatalf("delete: got status %d: %s", status, b)
+	}
+	_, b = ts.do(http.MethodGet, "/v1/android_instances/"+created.Metadata.ID, nil)
+	var deleted instanceJSON
+	ts.decode(b, &deleted)
+	if deleted.Status.State != stateTerminated || deleted.Metadata.TerminatedAt == nil {
+		t.Errorf("get deleted: got %+v, want a terminated instance", deleted)
+	}
+	if status, _ := ts.do(http.MethodDelete, "/v1/android_instances/missing", nil); status != http.StatusNotFound {
+		t.Errorf("delete missing: got status %d, want %d", status, http.StatusNotFound)
+	}
+}
+
+func TestInstanceInitialAssets(t *testing.T) {
+	ts := newTestServer(t)
+	req := map[string]any{
+		"spec": map[string]any{
+			"initialAssets": []map[string]any{{"kind": "App", "source": "AssetName", "assetName": "app.apk"}},
+		},
+	}
+	if status, b := ts.do(http.MethodPost, "/v1/android_instances", req); status != http.StatusBadRequest {
+		t.Errorf("create with missing asset: got status %d: %s", status, b)
+	}
+	ts.PutAsset("app.apk", []byte("fake apk"))
+	if status, b := ts.do(http.MethodPost, "/v1/android_instances", req); status != http.StatusOK {
+		t.Errorf("create with uploaded asset: got status %d: %s", status, b)
+	}
+	if status, _ := ts.do(http.MethodPost, "/v1/android_instances", []byte("{")); status != http.StatusBadRequest {
+		t.Errorf("create with invalid body: got status %d, want %d", status, http.StatusBadRequest)
+	}
+}
+
+func TestListInstances(t *testing.T) {
+	ts := newTestServer(t)
+	for _, r := range []struct {
+		kind, region, team string
+	}{
+		{"android", "eu", "qa"},
+		{"android", "us", "qa"},
+		{"android", "eu", "dev"},
+		{"ios", "eu", "qa"},
+	} {
+		status, b := ts.do(http.MethodPost, "/v1/"+r.kind+"_instances", map[string]any{
+			"metadata": map[string]any{"labels": map[string]string{"team": r.team}},
+			"spec":     map[string]any{"region": r.region},
+		})
+		if status != http.StatusOK {
+			t.Fatalf("create: got status %d: %s", status, b)
+		}
+	}
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"", 3},
+		{"?region=eu", 2},
+		{"?labelSelector=team%3Dqa", 2},
+		{"?labelSelector=team%3Dqa&region=eu", 1},
+		{"?state=terminated", 0},
+	} {
+		status, b := ts.do(http.MethodGet, "/v1/android_instances"+tc.query, nil)
+		if status != http.StatusOK {
+			t.Fatalf("list %s: got status %d: %s", tc.query, status, b)
+		}
+		var list []instanceJSON
+		ts.decode(b, &list)
+		if len(list) != tc.want {
+			t.Errorf("list %q: got %d instances, want %d", tc.query, len(list), tc.want)
+		}
+	}
+}
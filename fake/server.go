@@ -0,0 +1,182 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements an in-memory Limrun API server for testing
+// scripts and the CLI without creating real instances. It serves the asset
+// and instance endpoints, signed upload and download URLs, and WebSocket
+// endpoints of the instances.
+package fake
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.jetify.com/typeid/v2"
+)
+
+// signingKey signs the storage URLs. It's fixed so that the URLs are the same
+// across runs.
+const signingKey = "lim-fake-server"
+
+// defaultURLExpiry is how long signed URLs are valid.
+const defaultURLExpiry = time.Hour
+
+// Server is a fake Limrun API server. IDs, tokens and signatures are
+// deterministic so that the output of commands run against it can be
+// compared across runs.
+type Server struct {
+	// APIKey is the key that requests need to carry. Any key is accepted if
+	// it's empty.
+	APIKey string
+
+	// ReadyAfter is how long instances stay in creating state.
+	ReadyAfter time.Duration
+
+	// Now returns the current time, time.Now if nil.
+	Now func() time.Time
+
+	mu        sync.Mutex
+	seq       uint64
+	assets    map[string]*asset
+	instances map[string]*instance
+
+	srv      *http.Server
+	listener net.Listener
+}
+
+// New returns a server with no assets or instances.
+func New() *Server {
+	return &Server{
+		assets:    map[string]*asset{},
+		instances: map[string]*instance{},
+	}
+}
+
+// Handler returns the handler that serves the API, e.g. to use with
+// httptest.NewServer.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/assets", s.auth(s.listAssets))
+	mux.HandleFunc("PUT /v1/assets", s.auth(s.getOrNewAsset))
+	mux.HandleFunc("GET /v1/assets/{id}", s.auth(s.getAsset))
+	// The SDK has no asset deletion, this is the endpoint that lim assumes.
+	mux.HandleFunc("DELETE /v1/assets/{id}", s.auth(s.deleteAsset))
+	for _, kind := range []string{kindAndroid, kindIOS} {
+		base := "/v1/" + kind + "_instances"
+		mux.HandleFunc("POST "+base, s.auth(s.newInstance(kind)))
+		mux.HandleFunc("GET "+base, s.auth(s.listInstances(kind)))
+		mux.HandleFunc("GET "+base+"/{id}", s.auth(s.getInstance(kind)))
+		mux.HandleFunc("DELETE "+base+"/{id}", s.auth(s.deleteInstance(kind)))
+	}
+	mux.HandleFunc("/storage/{id}", s.storage)
+	mux.HandleFunc("/adb/{id}", s.adb)
+	mux.HandleFunc("/endpoint/{id}", s.endpoint)
+	return mux
+}
+
+// Start starts serving on the given address, e.g. 127.0.0.1:0 to pick a
+// free port.
+func (s *Server) Start(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.listener = l
+	s.srv = &http.Server{Handler: s.Handler()}
+	go func() {
+		_ = s.srv.Serve(l)
+	}()
+	return nil
+}
+
+// URL returns the base URL of the started server to be used as the API
+// endpoint.
+func (s *Server) URL() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+// Close stops the server.
+func (s *Server) Close() error {
+	if s.srv == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// newID returns the next ID with the given prefix. mu must be held.
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b[8:], s.seq)
+	id, _ := typeid.FromBytes(prefix, b)
+	return id.String()
+}
+
+// auth rejects requests without the API key the same way the API does.
+func (s *Server) auth(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || key == "" || (s.APIKey != "" && key != s.APIKey) {
+			writeError(w, http.StatusUnauthorized, "unauthenticated: invalid API key")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// baseURL returns the URL that the client used to reach the server.
+func baseURL(r *http.Request, scheme string) string {
+	if r.TLS != nil {
+		scheme += "s"
+	}
+	return scheme + "://" + r.Host
+}
+
+// sign returns the signature of a storage URL.
+func sign(method, id, date, expires string) string {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(method + "\n" + id + "\n" + date + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"message": message})
+}
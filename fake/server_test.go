@@ -0,0 +1,124 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testKey = "test-key"
+
+// testServer serves a fake with a clock that tests can move.
+type testServer struct {
+	*Server
+	t   *testing.T
+	url string
+	now time.Time
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	ts := &testServer{
+		Server: New(),
+		t:      t,
+		now:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	ts.APIKey = testKey
+	ts.Now = func() time.Time { return ts.now }
+	srv := httptest.NewServer(ts.Handler())
+	t.Cleanup(srv.Close)
+	ts.url = srv.URL
+	return ts
+}
+
+// do sends the request with the API key and returns the status and the body.
+// The body is encoded as JSON unless it's a byte slice. Relative URLs are
+// resolved against the server.
+func (ts *testServer) do(method, url string, body any, header ...string) (int, []byte) {
+	ts.t.Helper()
+	var r io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		r = bytes.NewReader(b)
+	default:
+		j, err := json.Marshal(b)
+		if err != nil {
+			ts.t.Fatal(err)
+		}
+		r = bytes.NewReader(j)
+	}
+	if len(url) > 0 && url[0] == '/' {
+		url = ts.url + url
+	}
+	req, err := http.NewRequest(method, url, r)
+	if err != nil {
+		ts.t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testKey)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		ts.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		ts.t.Fatal(err)
+	}
+	return resp.StatusCode, b
+}
+
+// decode decodes the JSON response body into v.
+func (ts *testServer) decode(b []byte, v any) {
+	ts.t.Helper()
+	if err := json.Unmarshal(b, v); err != nil {
+		ts.t.Fatalf("failed to decode %s: %s", b, err)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	ts := newTestServer(t)
+	for _, key := range []string{"", "wrong-key"} {
+		req, err := http.NewRequest(http.MethodGet, ts.url+"/v1/assets", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("key %q: got status %d, want %d", key, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+	if status, b := ts.do(http.MethodGet, "/v1/assets", nil); status != http.StatusOK {
+		t.Errorf("valid key: got status %d: %s", status, b)
+	}
+}
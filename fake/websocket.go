@@ -0,0 +1,94 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+)
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// upgrade authenticates the connection with the token of the instance and
+// upgrades it if the instance is ready.
+func (s *Server) upgrade(w http.ResponseWriter, r *http.Request) (*instance, *websocket.Conn, bool) {
+	s.mu.Lock()
+	i, ok := s.instances[r.PathValue("id")]
+	var ready bool
+	if ok {
+		ready = s.state(i, s.now()) == stateReady
+	}
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "instance not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+	if token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); token != i.Token {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return nil, nil, false
+	}
+	if !ready {
+		http.Error(w, "instance is not ready", http.StatusServiceUnavailable)
+		return nil, nil, false
+	}
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return nil, nil, false
+	}
+	return i, ws, true
+}
+
+// adb echoes everything sent to it back, which is enough for the tunnel to
+// be started and data to flow through it.
+func (s *Server) adb(w http.ResponseWriter, r *http.Request) {
+	i, ws, ok := s.upgrade(w, r)
+	if !ok {
+		return
+	}
+	defer ws.Close()
+	if i.Kind != kindAndroid {
+		return
+	}
+	for {
+		typ, data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		if err := ws.WriteMessage(typ, data); err != nil {
+			return
+		}
+	}
+}
+
+// endpoint accepts connections to the instance endpoint. Its protocol is
+// not documented, so requests are read and left unanswered.
+func (s *Server) endpoint(w http.ResponseWriter, r *http.Request) {
+	_, ws, ok := s.upgrade(w, r)
+	if !ok {
+		return
+	}
+	defer ws.Close()
+	for {
+		if _, _, err := ws.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
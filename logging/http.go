@@ -0,0 +1,162 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/limrun-inc/go-sdk/option"
+)
+
+const (
+	// maxBody is how much of a body is logged.
+	maxBody = 4 << 10
+
+	redacted = "REDACTED"
+)
+
+// secretKeys are the parts of the names of query parameters and JSON fields
+// whose values are not logged.
+var secretKeys = []string{"token", "key", "secret", "password", "signature", "credential"}
+
+// Middleware logs every request with its method, URL, status, latency and
+// the request ID the API assigned at debug level. If bodies is true, JSON
+// request and response bodies are logged too. Secrets in URLs and bodies,
+// e.g. instance tokens and URL signatures, are redacted.
+func Middleware(bodies bool) option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		logger := slog.Default()
+		ctx := req.Context()
+		if !logger.Enabled(ctx, slog.LevelDebug) {
+			return next(req)
+		}
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.String("url", RedactURL(req.URL.String())),
+		}
+		if bodies && req.GetBody != nil && isJSON(req.Header) {
+			if body, err := req.GetBody(); err == nil {
+				b, _ := io.ReadAll(io.LimitReader(body, maxBody))
+				_ = body.Close()
+				attrs = append(attrs, slog.String("request_body", redactBody(b)))
+			}
+		}
+		start := time.Now()
+		resp, err := next(req)
+		attrs = append(attrs, slog.Duration("latency", time.Since(start)))
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelDebug, "http request failed", append(attrs, slog.Any("error", err))...)
+			return resp, err
+		}
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if id := resp.Header.Get("X-Request-Id"); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		if bodies && isJSON(resp.Header) {
+			// Only the beginning is read and it's put back in front of the
+			// rest so that the caller gets the whole body.
+			b, _ := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+			attrs = append(attrs, slog.String("response_body", redactBody(b)))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "http request", attrs...)
+		return resp, nil
+	}
+}
+
+// RedactURL replaces the values of query parameters that carry secrets,
+// e.g. the signature of a signed URL.
+func RedactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.RawQuery == "" {
+		return s
+	}
+	q := u.Query()
+	for k := range q {
+		if isSecret(k) {
+			q.Set(k, redacted)
+		}
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, k := range secretKeys {
+		if strings.Contains(name, k) {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSON(h http.Header) bool {
+	return strings.Contains(h.Get("Content-Type"), "json")
+}
+
+// redactBody returns the JSON body with secrets redacted. Bodies that are
+// cut at maxBody or are not valid JSON otherwise are summarized by size.
+func redactBody(b []byte) string {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fmt.Sprintf("(%d bytes)", len(b))
+	}
+	var out strings.Builder
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(redactValue(v)); err != nil {
+		return fmt.Sprintf("(%d bytes)", len(b))
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			if _, ok := e.(string); ok && isSecret(k) {
+				v[k] = redacted
+				continue
+			}
+			v[k] = redactValue(e)
+		}
+		return v
+	case []any:
+		for i, e := range v {
+			v[i] = redactValue(e)
+		}
+		return v
+	case string:
+		if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+			return RedactURL(v)
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging sets up the logger of lim and logs the requests to the
+// Limrun API.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup installs the default logger that writes to w in the given format.
+// Warnings and errors are logged by default, verbosity 1 adds info and 2
+// or more adds debug messages. debugHTTP turns on debug messages since
+// requests are logged at debug level.
+func Setup(w io.Writer, format string, verbosity int, debugHTTP bool) error {
+	level := slog.LevelWarn
+	switch {
+	case verbosity >= 2 || debugHTTP:
+		level = slog.LevelDebug
+	case verbosity == 1:
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch format {
+	case FormatText, "":
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("invalid log format %q, must be one of text or json", format)
+	}
+	slog.SetDefault(slog.New(h))
+	// Dependencies that use the log package, e.g. the ADB tunnel, report
+	// failures through it, so they're shown by default.
+	slog.SetLogLoggerLevel(slog.LevelWarn)
+	return nil
+}
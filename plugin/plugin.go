@@ -0,0 +1,125 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin finds the executables named lim-<name> on PATH that extend
+// lim with commands, e.g. lim-smoke is run as lim smoke.
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// Prefix is the prefix of the file names of plugins.
+const Prefix = "lim-"
+
+// Plugin is an executable found on PATH.
+type Plugin struct {
+	// Name is the file name without the prefix and the extension, e.g.
+	// smoke-android for lim-smoke-android, which is run as either
+	// lim smoke-android or lim smoke android.
+	Name string
+	Path string
+	// ShadowedBy is the path of the plugin with the same name that comes
+	// earlier on PATH, if any.
+	ShadowedBy string
+}
+
+// List returns the plugins on PATH in the order they are looked up. A name
+// may appear several times if it's in several directories, in which case
+// only the first one is run.
+func List() []Plugin {
+	var plugins []Plugin
+	first := map[string]string{}
+	seenDirs := map[string]bool{}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" || seenDirs[dir] {
+			continue
+		}
+		seenDirs[dir] = true
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := pluginName(e.Name())
+			if !ok || e.IsDir() {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			p := Plugin{Name: name, Path: path, ShadowedBy: first[name]}
+			if p.ShadowedBy == "" {
+				first[name] = path
+			}
+			plugins = append(plugins, p)
+		}
+	}
+	return plugins
+}
+
+// Find returns the path of the plugin that handles the arguments and the
+// arguments to run it with. The longest match wins, e.g. lim-smoke-android
+// over lim-smoke for smoke android. Only the leading arguments that aren't
+// flags make up the name.
+func Find(args []string) (string, []string, bool) {
+	n := slices.IndexFunc(args, func(a string) bool { return strings.HasPrefix(a, "-") })
+	if n < 0 {
+		n = len(args)
+	}
+	paths := map[string]string{}
+	for _, p := range List() {
+		if p.ShadowedBy == "" {
+			paths[p.Name] = p.Path
+		}
+	}
+	for i := n; i > 0; i-- {
+		if path, ok := paths[strings.Join(args[:i], "-")]; ok {
+			return path, args[i:], true
+		}
+	}
+	return "", nil, false
+}
+
+// pluginName returns the name of the plugin with the given file name.
+func pluginName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, Prefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".exe" && ext != ".bat" && ext != ".cmd" {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	return name, name != ""
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	// Windows has no executable bit, the extension is checked instead.
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/limrun-inc/lim/cache"
+	"github.com/limrun-inc/lim/version"
+)
+
+const (
+	// checkInterval is how often GitHub is asked for the latest release
+	// and how often a newer one is announced.
+	checkInterval = 24 * time.Hour
+
+	// checkTimeout bounds the check so that it doesn't outlive short
+	// commands.
+	checkTimeout = 5 * time.Second
+)
+
+// state is the file the result of the last check is kept in.
+type state struct {
+	CheckedAt  time.Time `json:"checkedAt"`
+	NotifiedAt time.Time `json:"notifiedAt"`
+	Latest     string    `json:"latest"`
+	URL        string    `json:"url"`
+}
+
+func statePath() string {
+	return filepath.Join(cache.Dir(), "release.json")
+}
+
+func loadState() state {
+	var s state
+	b, err := os.ReadFile(statePath())
+	if err != nil {
+		return s
+	}
+	// A corrupt file is as good as no check at all.
+	_ = json.Unmarshal(b, &s)
+	return s
+}
+
+// saveState writes the state file atomically since the check in the
+// background may write it at the same time. Errors are ignored since a
+// failed check is simply repeated next time.
+func saveState(s state) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath()), 0700); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(filepath.Dir(statePath()), "release-*.json")
+	if err != nil {
+		return
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return
+	}
+	if err := f.Close(); err != nil {
+		return
+	}
+	_ = os.Rename(f.Name(), statePath())
+}
+
+// Check looks up the latest release in the background unless it was done
+// within the last day. The result is picked up by Notice, possibly in a
+// later run if lim exits before the check finishes.
+func Check() {
+	if time.Since(loadState().CheckedAt) < checkInterval {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+		defer cancel()
+		r, err := Latest(ctx)
+		if err != nil {
+			return
+		}
+		s := loadState()
+		s.CheckedAt = time.Now()
+		s.Latest = r.Version
+		s.URL = r.URL
+		saveState(s)
+	}()
+}
+
+// Notice returns the message that announces a release newer than the
+// running version, or an empty string if there is none or it was announced
+// within the last day already.
+func Notice() string {
+	s := loadState()
+	if s.Latest == "" || !Newer(s.Latest, version.Version) || time.Since(s.NotifiedAt) < checkInterval {
+		return ""
+	}
+	s.NotifiedAt = time.Now()
+	saveState(s)
+	return fmt.Sprintf("A new version of lim is available: %s -> %s\nRun lim upgrade or see %s", version.Version, s.Latest, s.URL)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package release finds the releases of lim on GitHub and installs them.
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/limrun-inc/lim/version"
+)
+
+// URL is the GitHub API endpoint that returns the latest release of lim.
+var URL = "https://api.github.com/repos/limrun-inc/lim/releases/latest"
+
+// Release is a published release of lim.
+type Release struct {
+	Version string  `json:"tag_name"`
+	URL     string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	// Digest is the checksum GitHub computed for the file, e.g.
+	// sha256:<hex>.
+	Digest string `json:"digest"`
+}
+
+// Latest returns the latest release of lim.
+func Latest(ctx context.Context) (*Release, error) {
+	resp, err := get(ctx, URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest release: %w", err)
+	}
+	defer resp.Body.Close()
+	var r Release
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return nil, fmt.Errorf("failed to decode the latest release: %w", err)
+	}
+	if r.Version == "" {
+		return nil, fmt.Errorf("latest release has no version")
+	}
+	return &r, nil
+}
+
+// ArtifactName returns the name of the binary built for the given platform
+// by the release workflow, e.g. lim-darwin-arm64.
+func ArtifactName(goos, goarch string) string {
+	return "lim-" + goos + "-" + goarch
+}
+
+// Asset returns the asset with the given name.
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Install downloads the binary of the current platform from the release and
+// replaces the file at path with it once its SHA256 checksum is verified.
+// The replacement is atomic so path is either the old or the new binary
+// even if lim is interrupted.
+func Install(ctx context.Context, r *Release, path string) error {
+	name := ArtifactName(runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		return fmt.Errorf("upgrading is not supported on windows, download %s.zip from %s", name, r.URL)
+	}
+	bin, ok := r.Asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s", r.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	want, err := checksum(ctx, r, bin)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	// The new binary is written next to the old one so that renaming it
+	// doesn't cross file systems.
+	f, err := os.CreateTemp(filepath.Dir(path), ".lim-upgrade-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	resp, err := get(ctx, bin.URL)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, h), resp.Body); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("checksum of %s does not match, got %s but want %s", name, got, want)
+	}
+	if err := os.Chmod(f.Name(), info.Mode().Perm()|0111); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// checksum returns the SHA256 checksum of the binary, read from the
+// <name>.sha256 file of the release if there is one and from the digest
+// GitHub computed otherwise.
+func checksum(ctx context.Context, r *Release, bin Asset) (string, error) {
+	if a, ok := r.Asset(bin.Name + ".sha256"); ok {
+		resp, err := get(ctx, a.URL)
+		if err != nil {
+			return "", fmt.Errorf("failed to download %s: %w", a.Name, err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if err != nil {
+			return "", fmt.Errorf("failed to download %s: %w", a.Name, err)
+		}
+		// The file is in the format of sha256sum, i.e. the checksum is
+		// followed by the file name.
+		if fields := strings.Fields(string(b)); len(fields) > 0 {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if sum, ok := strings.CutPrefix(bin.Digest, "sha256:"); ok {
+		return strings.ToLower(sum), nil
+	}
+	return "", fmt.Errorf("release %s has no SHA256 checksum of %s", r.Version, bin.Name)
+}
+
+func get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "lim/"+version.Version)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp, nil
+}
+
+// Newer returns whether version a is newer than version b. Versions are
+// compared as vMAJOR.MINOR.PATCH. Anything after a dash that starts with a
+// letter, e.g. -rc.1, makes a version older than the same one without it,
+// while a number after the dash is the count of commits after the tag of
+// builds between releases, e.g. v1.2.3-4.gabcdef, which makes it newer.
+func Newer(a, b string) bool {
+	va, vb := parse(a), parse(b)
+	for i := range va.parts {
+		if va.parts[i] != vb.parts[i] {
+			return va.parts[i] > vb.parts[i]
+		}
+	}
+	if (va.pre == "") != (vb.pre == "") {
+		return va.pre == ""
+	}
+	return va.commits > vb.commits
+}
+
+// parsed is a version split into its parts.
+type parsed struct {
+	parts [3]int
+
+	// pre is the prerelease suffix, e.g. rc.1.
+	pre string
+
+	// commits is the number of commits after the tag.
+	commits int
+}
+
+func parse(v string) parsed {
+	var p parsed
+	v, suffix, _ := strings.Cut(strings.TrimPrefix(v, "v"), "-")
+	for i, s := range strings.SplitN(v, ".", 3) {
+		if end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			s = s[:end]
+		}
+		p.parts[i], _ = strconv.Atoi(s)
+	}
+	// Builds of commits after a tag are described as v1.2.3-4.gabcdef with
+	// .dirty at the end if the tree was modified.
+	if n, err := strconv.Atoi(strings.SplitN(suffix, ".", 2)[0]); err == nil {
+		p.commits = n
+	} else if suffix != "dirty" {
+		p.pre = suffix
+	}
+	return p
+}
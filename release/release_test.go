@@ -0,0 +1,48 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import "testing"
+
+func TestNewer(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want bool
+	}{
+		{"v1.2.4", "v1.2.3", true},
+		{"v1.2.3", "v1.2.4", false},
+		{"v1.10.0", "v1.9.9", true},
+		{"v2.0.0", "v1.99.99", true},
+		{"v1.2.3", "v1.2.3", false},
+		{"v1.2.3", "v1.2.3-rc.1", true},
+		{"v1.2.3-rc.1", "v1.2.3", false},
+		{"v1.2.3-rc.1", "v1.2.2", true},
+		// Builds of commits after a tag.
+		{"v1.2.3", "v1.2.3-4.gabcdef", false},
+		{"v1.2.3-4.gabcdef", "v1.2.3", true},
+		{"v1.2.4", "v1.2.3-4.gabcdef", true},
+		{"v1.2.3-5.g0123456", "v1.2.3-4.gabcdef", true},
+		{"v1.2.3", "v1.2.3-4.gabcdef.dirty", false},
+		{"v1.2.3", "v1.2.3-dirty", false},
+		{"v1.2.3-rc.1", "v1.2.3-4.gabcdef", false},
+		{"v0.0.1", "v0.0.0-12.gabcdef", true},
+	} {
+		if got := Newer(tc.a, tc.b); got != tc.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retry retries the requests to the Limrun API that failed
+// temporarily.
+package retry
+
+import (
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/limrun-inc/go-sdk/option"
+
+	"github.com/limrun-inc/lim/logging"
+)
+
+// maxBackoff caps the exponential backoff between attempts.
+const maxBackoff = 30 * time.Second
+
+// Middleware retries requests that failed with a connection error, a
+// timeout, a rate limit or a server error up to maxRetries times. Requests
+// that are not idempotent, e.g. the POST that creates an instance, may have
+// taken effect despite the failure, so they are only retried when the server
+// says so with X-Should-Retry: true or with Retry-After on a 429 or 503
+// response. The delay
+// before attempt n is backoff*2^(n-1) with jitter unless a 429 or 503
+// response says how long to wait in Retry-After. Waiting ends early when
+// the context of the request is done, in which case the last response or
+// error is returned.
+//
+// The retries of the SDK itself should be turned off with
+// option.WithMaxRetries(0) when this is used.
+func Middleware(maxRetries int, backoff time.Duration) option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		// Requests whose body can't be read again are not retried.
+		if req.Body != nil && req.GetBody == nil {
+			return next(req)
+		}
+		for attempt := 0; ; attempt++ {
+			r := req
+			if attempt > 0 && req.GetBody != nil {
+				body, err := req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+				r = req.Clone(req.Context())
+				r.Body = body
+			}
+			resp, err := next(r)
+			if attempt >= maxRetries || !shouldRetry(req, resp, err) {
+				return resp, err
+			}
+			delay, ok := retryAfter(resp)
+			if !ok {
+				delay = exponential(backoff, attempt)
+			}
+			if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < delay {
+				return resp, err
+			}
+			attrs := []any{"method", req.Method, "url", logging.RedactURL(req.URL.String()), "attempt", attempt + 1, "delay", delay}
+			if resp != nil {
+				attrs = append(attrs, "status", resp.StatusCode)
+				_ = resp.Body.Close()
+			} else {
+				attrs = append(attrs, "error", err)
+			}
+			slog.Info("retrying request", attrs...)
+			t := time.NewTimer(delay)
+			select {
+			case <-req.Context().Done():
+				t.Stop()
+				return nil, req.Context().Err()
+			case <-t.C:
+			}
+		}
+	}
+}
+
+// shouldRetry returns whether the failure may go away when the request is
+// sent again.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if !idempotent(req.Method) {
+		if err != nil {
+			return false
+		}
+		if resp.Header.Get("X-Should-Retry") == "true" {
+			return true
+		}
+		_, ok := retryAfter(resp)
+		return ok
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.Header.Get("X-Should-Retry") {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode >= http.StatusInternalServerError
+}
+
+// idempotent returns whether sending a request with the given method twice
+// has the same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter returns the delay that a 429 or 503 response asks for in
+// seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// exponential returns the backoff before the attempt after the given one,
+// with up to a quarter taken off randomly so that clients don't retry in
+// lockstep.
+func exponential(base time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	d := base << attempt
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+	if q := int64(d / 4); q > 0 {
+		d -= time.Duration(rand.Int64N(q))
+	}
+	return d
+}
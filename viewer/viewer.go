@@ -0,0 +1,139 @@
+/*
+Copyright 2025 Limrun, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package viewer
+
+import (
+	"context"
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Server serves a browser-based player for a single instance on localhost and
+// proxies the signaling WebSocket of the instance, including the input events
+// that the player sends back.
+type Server struct {
+	// RemoteURL is the endpoint WebSocket URL of the instance.
+	RemoteURL string
+
+	// Token is used to authenticate against the instance.
+	Token string
+
+	listener net.Listener
+	srv      *http.Server
+	secret   string
+}
+
+// New returns a new Server that listens on an available port on localhost.
+func New(remoteURL, token string) (*Server, error) {
+	if remoteURL == "" {
+		return nil, fmt.Errorf("instance does not expose a streaming endpoint")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("creating a tcp listener failed: %w", err)
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	s := &Server{
+		RemoteURL: remoteURL,
+		Token:     token,
+		listener:  listener,
+		secret:    hex.EncodeToString(b),
+	}
+	mux := http.NewServeMux()
+	// The secret in the path makes sure only the browser we open can reach
+	// the instance, not any other local process or web page.
+	mux.HandleFunc("GET /"+s.secret+"/{$}", s.serveIndex)
+	mux.HandleFunc("GET /"+s.secret+"/signaling", s.serveSignaling)
+	s.srv = &http.Server{Handler: mux}
+	return s, nil
+}
+
+// URL returns the address of the player.
+func (s *Server) URL() string {
+	return fmt.Sprintf("http://%s/%s/", s.listener.Addr().String(), s.secret)
+}
+
+// Start starts serving in the background.
+func (s *Server) Start() {
+	go func() {
+		if err := s.srv.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("viewer server failed", "err", err)
+		}
+	}()
+}
+
+// Close stops the server and closes all active connections.
+func (s *Server) Close(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(indexHTML)
+}
+
+var upgrader = websocket.Upgrader{}
+
+func (s *Server) serveSignaling(w http.ResponseWriter, r *http.Request) {
+	remote, _, err := websocket.DefaultDialer.DialContext(r.Context(), s.RemoteURL, http.Header{
+		"Authorization": []string{fmt.Sprintf("Bearer %s", s.Token)},
+	})
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to dial instance: %s", err), http.StatusBadGateway)
+		return
+	}
+	defer remote.Close()
+	local, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer local.Close()
+	errc := make(chan error, 2)
+	go pipe(local, remote, errc)
+	go pipe(remote, local, errc)
+	<-errc
+}
+
+// pipe copies messages from src to dst until either of them fails.
+func pipe(dst, src *websocket.Conn, errc chan<- error) {
+	for {
+		typ, msg, err := src.ReadMessage()
+		if err != nil {
+			errc <- err
+			return
+		}
+		if err := dst.WriteMessage(typ, msg); err != nil {
+			errc <- err
+			return
+		}
+	}
+}